@@ -0,0 +1,201 @@
+package scrape
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The tags that arrange the elements of slices after they are scraped.
+// They are applied in the order: unique, sort, limit.
+const (
+	UniqueTag = "unique" // drop the elements with repeated values of the field ("URL"), or repeated elements if empty
+	SortTag   = "sort"   // sort the elements stably by the fields ("Price desc, Name"), or by themselves ("desc")
+	LimitTag  = "limit"  // keep at most the given number of the first elements ("10")
+)
+
+// sortKey is a key of the [SortTag].
+type sortKey struct {
+	path []string
+	desc bool
+}
+
+// arrangeSlice returns the slice with the elements arranged according to
+// the [UniqueTag], [SortTag], and [LimitTag].
+func arrangeSlice(sv reflect.Value, tag reflect.StructTag) (reflect.Value, error) {
+	elems := make([]reflect.Value, sv.Len())
+	for i := range elems {
+		elems[i] = sv.Index(i)
+	}
+
+	if key, ok := tag.Lookup(UniqueTag); ok {
+		var err error
+		elems, err = uniqueElems(elems, splitPath(key))
+		if err != nil {
+			return sv, SliceTagErr{Tag: UniqueTag, Value: key, Cause: err}
+		}
+	}
+
+	if keys, ok := tag.Lookup(SortTag); ok {
+		err := sortElems(elems, parseSortKeys(keys))
+		if err != nil {
+			return sv, SliceTagErr{Tag: SortTag, Value: keys, Cause: err}
+		}
+	}
+
+	if limit, ok := tag.Lookup(LimitTag); ok {
+		n, err := strconv.Atoi(strings.TrimSpace(limit))
+		if err != nil || n < 0 {
+			return sv, SliceTagErr{Tag: LimitTag, Value: limit, Cause: errors.New("not a non-negative integer")}
+		}
+		elems = elems[:min(n, len(elems))]
+	}
+
+	arranged := reflect.MakeSlice(sv.Type(), 0, len(elems))
+	return reflect.Append(arranged, elems...), nil
+}
+
+// uniqueElems returns the elements without the ones whose values at the
+// path repeat the values of the previous elements.
+func uniqueElems(elems []reflect.Value, path []string) ([]reflect.Value, error) {
+	unique := []reflect.Value{}
+	keys := []reflect.Value{}
+	seen := map[any]bool{}
+	for _, elem := range elems {
+		key, err := valueAt(elem, path)
+		if err != nil {
+			return nil, err
+		}
+		if key.Comparable() {
+			k := key.Interface()
+			if seen[k] {
+				continue
+			}
+			seen[k] = true
+		} else if slices.ContainsFunc(keys, func(k reflect.Value) bool { return reflect.DeepEqual(k.Interface(), key.Interface()) }) {
+			continue
+		}
+		keys = append(keys, key)
+		unique = append(unique, elem)
+	}
+	return unique, nil
+}
+
+// sortElems sorts the elements stably by the keys.
+func sortElems(elems []reflect.Value, keys []sortKey) error {
+	var err error
+	slices.SortStableFunc(elems, func(a, b reflect.Value) int {
+		for _, key := range keys {
+			va, errA := valueAt(a, key.path)
+			vb, errB := valueAt(b, key.path)
+			if err = cmp.Or(err, errA, errB); err != nil {
+				return 0
+			}
+			c, cmpErr := compareValues(va, vb)
+			if err = cmp.Or(err, cmpErr); err != nil {
+				return 0
+			}
+			if key.desc {
+				c = -c
+			}
+			if c != 0 {
+				return c
+			}
+		}
+		return 0
+	})
+	return err
+}
+
+// parseSortKeys parses the value of the [SortTag].
+func parseSortKeys(keys string) []sortKey {
+	parsed := []sortKey{}
+	for _, key := range strings.Split(keys, ",") {
+		words := strings.Fields(key)
+		k := sortKey{}
+		if len(words) != 0 {
+			switch strings.ToLower(words[len(words)-1]) {
+			case "desc":
+				k.desc = true
+				words = words[:len(words)-1]
+			case "asc":
+				words = words[:len(words)-1]
+			}
+		}
+		k.path = splitPath(strings.Join(words, ""))
+		parsed = append(parsed, k)
+	}
+	return parsed
+}
+
+// splitPath splits the path of fields separated by dots ("Offer.Price").
+func splitPath(path string) []string {
+	if path = strings.TrimSpace(path); path == "" {
+		return nil
+	}
+	return strings.Split(path, ".")
+}
+
+// valueAt returns the value of the field at the path in the element.
+// Pointers on the way are dereferenced, nil pointers give zero values.
+func valueAt(elem reflect.Value, path []string) (reflect.Value, error) {
+	for elem.Kind() == reflect.Pointer || (elem.Kind() == reflect.Interface && !elem.IsNil()) {
+		if elem.Kind() == reflect.Pointer && elem.IsNil() {
+			elem = reflect.Zero(elem.Type().Elem())
+			continue
+		}
+		elem = elem.Elem()
+	}
+	if len(path) == 0 {
+		return elem, nil
+	}
+	if elem.Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("%v has no field %s", elem.Type(), path[0])
+	}
+	field := elem.FieldByName(path[0])
+	if !field.IsValid() || !field.CanInterface() {
+		return reflect.Value{}, fmt.Errorf("%v has no field %s", elem.Type(), path[0])
+	}
+	return valueAt(field, path[1:])
+}
+
+// compareValues compares two values of the same type: strings, bools,
+// numbers, [time.Time], and [Money] (by the amount). Values of different
+// types, which elements of interface types can hold, cannot be compared.
+func compareValues(a, b reflect.Value) (int, error) {
+	if a.Type() != b.Type() {
+		return 0, fmt.Errorf("%v and %v cannot be compared", a.Type(), b.Type())
+	}
+	switch a.Type() {
+	case reflect.TypeFor[time.Time]():
+		return a.Interface().(time.Time).Compare(b.Interface().(time.Time)), nil
+	case reflect.TypeFor[Money]():
+		return cmp.Compare(a.Interface().(Money).Amount, b.Interface().(Money).Amount), nil
+	}
+
+	switch a.Kind() {
+	case reflect.String:
+		return cmp.Compare(a.String(), b.String()), nil
+	case reflect.Bool:
+		return cmp.Compare(boolInt(a.Bool()), boolInt(b.Bool())), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return cmp.Compare(a.Int(), b.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return cmp.Compare(a.Uint(), b.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return cmp.Compare(a.Float(), b.Float()), nil
+	}
+	return 0, fmt.Errorf("%v cannot be compared", a.Type())
+}
+
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
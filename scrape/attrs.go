@@ -0,0 +1,76 @@
+package scrape
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// ExtractAttributes returns all the attributes of the node.
+func ExtractAttributes(node *html.Node) map[string]string {
+	attrs := map[string]string{}
+	for _, a := range node.Attr {
+		attrs[a.Key] = a.Val
+	}
+	return attrs
+}
+
+// ExtractClasses returns the classes of the node in the order they are
+// listed in the class attribute.
+func ExtractClasses(node *html.Node) []string {
+	class, _ := ExtractAttribute(node, "class")
+	return strings.Fields(class)
+}
+
+// ExtractData returns the data-* attributes of the node by their names
+// without the "data-" prefix.
+func ExtractData(node *html.Node) map[string]string {
+	data := map[string]string{}
+	for _, a := range node.Attr {
+		if name, ok := strings.CutPrefix(a.Key, "data-"); ok && a.Namespace == "" {
+			data[name] = a.Val
+		}
+	}
+	return data
+}
+
+// ExtractStyle returns the declarations of the inline style of the node by
+// their lowercase property names. If a property is declared several times,
+// the last declaration wins.
+func ExtractStyle(node *html.Node) map[string]string {
+	style, _ := ExtractAttribute(node, "style")
+	declarations := map[string]string{}
+	for _, d := range splitDeclarations(style) {
+		property, value, ok := strings.Cut(d, ":")
+		property = strings.ToLower(strings.TrimSpace(property))
+		if ok && property != "" {
+			declarations[property] = strings.TrimSpace(value)
+		}
+	}
+	return declarations
+}
+
+// splitDeclarations splits the style by semicolons that are not enclosed
+// in parentheses or quotes ("background: url(data:image/png;base64,...)").
+func splitDeclarations(style string) []string {
+	declarations := []string{}
+	depth, quote, start := 0, rune(0), 0
+	for i, c := range style {
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth = max(depth-1, 0)
+		case c == ';' && depth == 0:
+			declarations = append(declarations, style[start:i])
+			start = i + 1
+		}
+	}
+	return append(declarations, style[start:])
+}
@@ -0,0 +1,24 @@
+package scrape_test
+
+import (
+	"testing"
+
+	. "github.com/branow/htmlscraper/scrape"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractStyle(t *testing.T) {
+	doc := getDoc(`<div style="COLOR: red; background: url(data:image/png;base64,AAA=); content: ';'; color: blue">`)
+	exp := map[string]string{
+		"color":      "blue",
+		"background": "url(data:image/png;base64,AAA=)",
+		"content":    "';'",
+	}
+	assert.Equal(t, exp, ExtractStyle(doc.Find("div").Nodes[0]))
+}
+
+func TestExtractData(t *testing.T) {
+	doc := getDoc(`<div id="x" data-product-id="12" data-stock="yes">`)
+	exp := map[string]string{"product-id": "12", "stock": "yes"}
+	assert.Equal(t, exp, ExtractData(doc.Find("div").Nodes[0]))
+}
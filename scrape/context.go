@@ -0,0 +1,109 @@
+package scrape
+
+import (
+	"context"
+	"net/url"
+	"reflect"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// ExtractContext is everything a [ContextExtractor] knows about the value
+// being scraped.
+type ExtractContext struct {
+	// Context is the context given to [Scraper.ScrapeContext].
+	Context context.Context
+
+	// Selection contains all the nodes matched by the selector.
+	Selection *goquery.Selection
+
+	// Root is the root node of the document.
+	Root *html.Node
+
+	// Field is the struct field being scraped, or the struct field of the
+	// slice, array or pointer whose element is being scraped. It is zero
+	// for the value given to [Scraper.Scrape].
+	Field reflect.StructField
+
+	// Type is the type of the value being scraped.
+	Type reflect.Type
+
+	// BaseURL is the URL that relative URLs are resolved against (see
+	// [Scraper.BaseURL]). It can be nil.
+	BaseURL *url.URL
+
+	// Extract is the value of the extract tag processed by the [Match].
+	Extract string
+}
+
+// ContextExtractor is a function that processes the given context and
+// returns the valuable data in string format. Unlike [Extractor], it sees
+// all the matched nodes and the context of the scraping.
+type ContextExtractor func(ec ExtractContext) (string, error)
+
+// AdaptExtractor converts the [Extractor] into a [ContextExtractor] that
+// processes the first node of the selection.
+func AdaptExtractor(extractor Extractor) ContextExtractor {
+	return func(ec ExtractContext) (string, error) {
+		if ec.Selection.Size() == 0 {
+			return "", NoNodesFoundErr{}
+		}
+		return extractor(ec.Selection.Nodes[0], ec.Extract)
+	}
+}
+
+// GetContextExtractorMap returns the default map to match extracting tags
+// and the extractors that need the context of the scraping. They are looked
+// up after the ones of [GetExtractorMap].
+func GetContextExtractorMap() map[*Match]ContextExtractor {
+	m := map[*Match]ContextExtractor{}
+
+	markdownMatch := GetEqualMatch(MarkdownExtractTag)
+	m[&markdownMatch] = func(ec ExtractContext) (string, error) {
+		if ec.Selection.Size() == 0 {
+			return "", NoNodesFoundErr{}
+		}
+		return ToMarkdown(ec.Selection.Nodes[0], ec.BaseURL), nil
+	}
+
+	imageMatch := GetEqualMatch(ImageExtractTag)
+	m[&imageMatch] = func(ec ExtractContext) (string, error) {
+		if ec.Selection.Size() == 0 {
+			return "", NoNodesFoundErr{}
+		}
+		image, err := ExtractImage(ec.Selection.Nodes[0])
+		if err != nil {
+			return "", err
+		}
+		return resolveURL(ec.BaseURL, image), nil
+	}
+
+	return m
+}
+
+// TypedExtractor is a function that processes the given context and returns
+// the valuable data as a value of type T, which is assigned to the scraped
+// value as it is, without formatting to string and parsing back.
+type TypedExtractor[T any] func(ec ExtractContext) (T, error)
+
+// ValueExtractor is the form of a [TypedExtractor] that the [Scraper] works
+// with regardless of the type parameter.
+type ValueExtractor interface {
+	// Type returns the type of the values returned by ExtractValue.
+	Type() reflect.Type
+	// ExtractValue processes the given context and returns the valuable
+	// data as a value of the type returned by Type.
+	ExtractValue(ec ExtractContext) (reflect.Value, error)
+}
+
+// Type returns the type T.
+func (e TypedExtractor[T]) Type() reflect.Type {
+	return reflect.TypeFor[T]()
+}
+
+// ExtractValue calls the extractor.
+func (e TypedExtractor[T]) ExtractValue(ec ExtractContext) (reflect.Value, error) {
+	val, err := e(ec)
+	return reflect.ValueOf(&val).Elem(), err
+}
@@ -0,0 +1,68 @@
+package scrape
+
+import (
+	"reflect"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Discriminator chooses the concrete type a node is scraped into when
+// the target is of an interface type. Discriminators are registered in
+// [Scraper.Discriminators] and the first one that matches the node wins.
+type Discriminator struct {
+	// Match reports whether the node (a selection of one node) is of
+	// the Type.
+	Match func(selection *goquery.Selection) bool
+
+	// Type is the concrete type that implements the interface type.
+	Type reflect.Type
+}
+
+// ByClass creates a Discriminator that chooses the type of o for nodes
+// with the given class.
+func ByClass(class string, o any) Discriminator {
+	return Discriminator{
+		Match: func(selection *goquery.Selection) bool {
+			return selection.HasClass(class)
+		},
+		Type: reflect.TypeOf(o),
+	}
+}
+
+// ByAttr creates a Discriminator that chooses the type of o for nodes
+// whose attribute attr has the given value.
+func ByAttr(attr, value string, o any) Discriminator {
+	return Discriminator{
+		Match: func(selection *goquery.Selection) bool {
+			v, ok := selection.Attr(attr)
+			return ok && v == value
+		},
+		Type: reflect.TypeOf(o),
+	}
+}
+
+// BySelector creates a Discriminator that chooses the type of o for
+// nodes matched by the given jQuery-like selector.
+func BySelector(selector string, o any) Discriminator {
+	return Discriminator{
+		Match: func(selection *goquery.Selection) bool {
+			return filterSelector(nil, selection, selector).Size() != 0
+		},
+		Type: reflect.TypeOf(o),
+	}
+}
+
+// discriminate returns the concrete type of the given interface type
+// for the node.
+func (scraper Scraper) discriminate(selection *goquery.Selection, ot reflect.Type) (reflect.Type, error) {
+	for _, d := range scraper.Discriminators[ot] {
+		if !d.Match(selection) {
+			continue
+		}
+		if d.Type == nil || !d.Type.Implements(ot) {
+			return nil, ImplementErr{Type: d.Type, Interface: ot}
+		}
+		return d.Type, nil
+	}
+	return nil, NoDiscriminatorErr{Interface: ot}
+}
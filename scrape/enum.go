@@ -0,0 +1,85 @@
+package scrape
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// EnumTag is a tag that maps the extracted data onto the values of enums
+// or constants: enum:"In stock=available,Out of stock=soldout,*=unknown".
+// Keys are compared with the data case-insensitively, ignoring the spaces
+// around it. A key enclosed in slashes is a case-insensitive regular
+// expression ("/^only \d+ left$/=available"). The "*" key maps the data that no other
+// key matches. Without it, the data that no key matches causes [EnumErr].
+const EnumTag = "enum"
+
+// EnumWildcard is the key of the [EnumTag] that matches any data.
+const EnumWildcard = "*"
+
+// enumCase maps the data matched by the key to the value.
+type enumCase struct {
+	key   string
+	regex *regexp.Regexp
+	value string
+}
+
+// parseEnum parses the value of the [EnumTag].
+func parseEnum(tag string) ([]enumCase, error) {
+	cases := []enumCase{}
+	for rest := strings.TrimSpace(tag); rest != ""; rest = strings.TrimSpace(rest) {
+		c := enumCase{}
+		if strings.HasPrefix(rest, "/") {
+			end := strings.Index(rest[1:], "/=")
+			if end < 0 {
+				return nil, EnumTagErr{Case: rest, Cause: errors.New(`missing "/="`)}
+			}
+			pattern := rest[1 : end+1]
+			regex, err := regexp.Compile("(?i)" + pattern)
+			if err != nil {
+				return nil, EnumTagErr{Case: "/" + pattern + "/", Cause: err}
+			}
+			c.regex = regex
+			c.value, rest, _ = strings.Cut(rest[end+3:], ",")
+			c.value = strings.TrimSpace(c.value)
+		} else {
+			var entry string
+			entry, rest, _ = strings.Cut(rest, ",")
+			key, value, ok := strings.Cut(entry, "=")
+			if !ok {
+				return nil, EnumTagErr{Case: entry, Cause: errors.New(`missing "="`)}
+			}
+			c.key = normalizeEnumKey(key)
+			c.value = strings.TrimSpace(value)
+		}
+		cases = append(cases, c)
+	}
+	return cases, nil
+}
+
+// mapEnum returns the value the data is mapped onto by the cases.
+func mapEnum(cases []enumCase, val string) (string, error) {
+	key := normalizeEnumKey(val)
+	wildcard, ok := "", false
+	for _, c := range cases {
+		switch {
+		case c.regex != nil:
+			if c.regex.MatchString(strings.TrimSpace(val)) {
+				return c.value, nil
+			}
+		case c.key == EnumWildcard:
+			wildcard, ok = c.value, true
+		case c.key == key:
+			return c.value, nil
+		}
+	}
+	if ok {
+		return wildcard, nil
+	}
+	return "", EnumErr{Value: val}
+}
+
+// normalizeEnumKey returns the key in lower case with collapsed spaces.
+func normalizeEnumKey(key string) string {
+	return strings.ToLower(strings.Join(strings.Fields(key), " "))
+}
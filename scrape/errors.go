@@ -2,6 +2,7 @@ package scrape
 
 import (
 	"fmt"
+	"reflect"
 	"strings"
 )
 
@@ -26,6 +27,18 @@ func (e AttributeNotFoundErr) Error() string {
 	return fmt.Sprintf("attribute \"%s\" not found", e.Attr)
 }
 
+type ImageNotFoundErr struct{}
+
+func (e ImageNotFoundErr) Error() string {
+	return "image not found"
+}
+
+type JSONNotFoundErr struct{}
+
+func (e JSONNotFoundErr) Error() string {
+	return "JSON not found"
+}
+
 type ExtractTagErr struct {
 	ExtractTag string
 }
@@ -74,3 +87,151 @@ type NilErr struct {
 func (e NilErr) Error() string {
 	return fmt.Sprintf("%s is nil", e.Var)
 }
+
+type DepthErr struct {
+	Depth int
+}
+
+func (e DepthErr) Error() string {
+	return fmt.Sprintf("maximum depth %d exceeded", e.Depth)
+}
+
+type ValidateTagErr struct {
+	Rule  string
+	Cause error
+}
+
+func (e ValidateTagErr) Error() string {
+	return fmt.Sprintf("invalid validation rule \"%s\": %v", e.Rule, e.Cause)
+}
+
+type ValidationErr struct {
+	Field string
+	Rule  string
+	Value any
+}
+
+func (e ValidationErr) Error() string {
+	if s, ok := e.Value.(string); ok {
+		return fmt.Sprintf("field %s: value %q violates rule \"%s\"", e.Field, s, e.Rule)
+	}
+	return fmt.Sprintf("field %s: value %v violates rule \"%s\"", e.Field, e.Value, e.Rule)
+}
+
+type NoDiscriminatorErr struct {
+	Interface reflect.Type
+}
+
+func (e NoDiscriminatorErr) Error() string {
+	return fmt.Sprintf("no discriminator of %v matches the node", e.Interface)
+}
+
+type ImplementErr struct {
+	Type      reflect.Type
+	Interface reflect.Type
+}
+
+func (e ImplementErr) Error() string {
+	return fmt.Sprintf("%v does not implement %v", e.Type, e.Interface)
+}
+
+type ArraySizeErr struct {
+	Len   int
+	Found int
+}
+
+func (e ArraySizeErr) Error() string {
+	return fmt.Sprintf("found %d nodes for an array of length %d", e.Found, e.Len)
+}
+
+type TypeErr struct {
+	Exp reflect.Type
+	Act reflect.Type
+}
+
+func (e TypeErr) Error() string {
+	return fmt.Sprintf("%v cannot be set to %v", e.Act, e.Exp)
+}
+
+type NumberErr struct {
+	Value  string
+	Locale string
+}
+
+func (e NumberErr) Error() string {
+	if e.Locale == "" {
+		return fmt.Sprintf("invalid number %q", e.Value)
+	}
+	return fmt.Sprintf("invalid number %q in locale %s", e.Value, e.Locale)
+}
+
+type LocaleErr struct {
+	Locale string
+}
+
+func (e LocaleErr) Error() string {
+	return fmt.Sprintf("unknown locale %q", e.Locale)
+}
+
+type TimeErr struct {
+	Value   string
+	Layouts []string
+}
+
+func (e TimeErr) Error() string {
+	return fmt.Sprintf("time %q matches neither layouts %q nor relative times", e.Value, e.Layouts)
+}
+
+type EnumTagErr struct {
+	Case  string
+	Cause error
+}
+
+func (e EnumTagErr) Error() string {
+	return fmt.Sprintf("invalid enum case \"%s\": %v", e.Case, e.Cause)
+}
+
+type EnumErr struct {
+	Value string
+}
+
+func (e EnumErr) Error() string {
+	return fmt.Sprintf("value %q is not mapped by the enum", e.Value)
+}
+
+type WhereTagErr struct {
+	Clause string
+	Cause  error
+}
+
+func (e WhereTagErr) Error() string {
+	return fmt.Sprintf("invalid where clause \"%s\": %v", e.Clause, e.Cause)
+}
+
+type SliceTagErr struct {
+	Tag   string
+	Value string
+	Cause error
+}
+
+func (e SliceTagErr) Error() string {
+	return fmt.Sprintf("invalid %s tag \"%s\": %v", e.Tag, e.Value, e.Cause)
+}
+
+type TemplateErr struct {
+	Template string
+	Cause    error
+}
+
+func (e TemplateErr) Error() string {
+	return fmt.Sprintf("invalid template \"%s\": %v", e.Template, e.Cause)
+}
+
+type JSONPathErr struct {
+	Path string
+	Key  string
+}
+
+func (e JSONPathErr) Error() string {
+	return fmt.Sprintf("JSON path \"%s\": key \"%s\" not found", e.Path, e.Key)
+}
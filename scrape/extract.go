@@ -8,11 +8,57 @@ import (
 
 // Extractor tags to specify extract operations.
 const (
-	TextExtractTag     = "text"     // get a text of children's text nodes
-	DeepTextExtractTag = "deeptext" // get a text of descendants' text nodes
-	AttrExtractTag     = "@"        // get a value of an attribute ("@href", "@src")
+	TextExtractTag        = "text"        // get a text of children's text nodes
+	DeepTextExtractTag    = "deeptext"    // get a text of descendants' text nodes
+	AttrExtractTag        = "@"           // get a value of an attribute ("@href", "@src")
+	TagExtractTag         = "tag"         // get a name of the element ("div", "img")
+	HTMLExtractTag        = "html"        // get the HTML of the children of the element
+	OuterHTMLExtractTag   = "outerhtml"   // get the HTML of the element itself
+	SafeHTMLExtractTag    = "safehtml"    // get the sanitized HTML of the children (see [Sanitizer])
+	PlainTextExtractTag   = "plaintext"   // get a text laid out as a browser renders it
+	VisibleTextExtractTag = "visibletext" // get a plain text without hidden elements
+	MarkdownExtractTag    = "markdown"    // get the content of the element as Markdown
+	ImageExtractTag       = "image"       // get the URL of the best image candidate (see [ExtractImage])
+	JSONExtractTag        = "json"        // get the JSON embedded in a script (see [ExtractJSON])
 )
 
+// URLExtractPrefix is a prefix of an extract tag that resolves the URL
+// extracted by the rest of the tag against the base URL ("url:@href",
+// "url:@src"). See [Scraper.BaseURL].
+const URLExtractPrefix = "url:"
+
+// Extractor tags to get several values of the node at once. Unlike the other
+// extractors, they are set to fields of the given types.
+const (
+	AttrsExtractTag   = "attrs"   // get all the attributes (map[string]string)
+	DataExtractTag    = "data"    // get the data-* attributes without the prefix (map[string]string)
+	StyleExtractTag   = "style"   // get the inline style declarations (map[string]string)
+	ClassesExtractTag = "classes" // get the class list ([]string)
+)
+
+// structuredExtractors matches the extract tags of several values to
+// the functions that extract them.
+var structuredExtractors = map[string]func(node *html.Node) any{
+	AttrsExtractTag:   func(node *html.Node) any { return ExtractAttributes(node) },
+	DataExtractTag:    func(node *html.Node) any { return ExtractData(node) },
+	StyleExtractTag:   func(node *html.Node) any { return ExtractStyle(node) },
+	ClassesExtractTag: func(node *html.Node) any { return ExtractClasses(node) },
+}
+
+// Extractor tags to get metadata of the matched nodes. They work even if
+// the selector matches nothing.
+const (
+	ExistsExtractTag = "exists" // whether the selector matches any node (bool)
+	CountExtractTag  = "count"  // number of nodes the selector matches (int)
+	IndexExtractTag  = "index"  // index of the element in the nearest scraped slice (int)
+)
+
+// isMetadataTag reports whether the extract tag gets metadata of the
+// matched nodes, so it does not need any node.
+func isMetadataTag(extract string) bool {
+	return extract == ExistsExtractTag || extract == CountExtractTag || extract == IndexExtractTag
+}
+
 // Extractor is a function that processes the given node and returns
 // the valuable data in string format.
 type Extractor func(node *html.Node, extract string) (string, error)
@@ -34,6 +80,41 @@ func GetExtractorMap() map[*Match]Extractor {
 		return data, nil
 	}
 
+	tagMatch := GetEqualMatch(TagExtractTag)
+	m[&tagMatch] = func(node *html.Node, extract string) (string, error) {
+		return node.Data, nil
+	}
+
+	plainTextMatch := GetEqualMatch(PlainTextExtractTag)
+	m[&plainTextMatch] = func(node *html.Node, extract string) (string, error) {
+		return ExtractPlainText(node, false), nil
+	}
+
+	visibleTextMatch := GetEqualMatch(VisibleTextExtractTag)
+	m[&visibleTextMatch] = func(node *html.Node, extract string) (string, error) {
+		return ExtractPlainText(node, true), nil
+	}
+
+	jsonMatch := GetEqualMatch(JSONExtractTag)
+	m[&jsonMatch] = func(node *html.Node, extract string) (string, error) {
+		return ExtractJSON(node)
+	}
+
+	htmlMatch := GetEqualMatch(HTMLExtractTag)
+	m[&htmlMatch] = func(node *html.Node, extract string) (string, error) {
+		return ExtractInnerHTML(node)
+	}
+
+	outerHTMLMatch := GetEqualMatch(OuterHTMLExtractTag)
+	m[&outerHTMLMatch] = func(node *html.Node, extract string) (string, error) {
+		return ExtractOuterHTML(node)
+	}
+
+	safeHTMLMatch := GetEqualMatch(SafeHTMLExtractTag)
+	m[&safeHTMLMatch] = func(node *html.Node, extract string) (string, error) {
+		return DefaultSanitizer().Sanitize(node)
+	}
+
 	attrMatch := GetPrefixMatch(AttrExtractTag)
 	m[&attrMatch] = ExtractAttribute
 
@@ -72,12 +153,33 @@ func ExtractText(node *html.Node) string {
 }
 
 // ExtractAttribute returns the value of the given attribute.
-// If the attribute is absent it returns an error.
+// If the attribute is absent it returns an error. A namespace-qualified
+// attribute of foreign content is specified with its namespace prefix
+// ("xlink:href"). An attribute without a prefix matches an attribute
+// without a namespace in the first place.
 func ExtractAttribute(node *html.Node, attr string) (string, error) {
-	for _, v := range node.Attr {
-		if v.Key == attr {
+	return extractAttribute(node, attr, func(a, b string) bool { return a == b })
+}
+
+// ExtractAttributeFold works as [ExtractAttribute] but matches the names of
+// attributes case-insensitively ("viewbox" matches "viewBox").
+func ExtractAttributeFold(node *html.Node, attr string) (string, error) {
+	return extractAttribute(node, attr, strings.EqualFold)
+}
+
+func extractAttribute(node *html.Node, attr string, equal func(a, b string) bool) (string, error) {
+	var namespaced *html.Attribute
+	for i, v := range node.Attr {
+		if v.Namespace == "" && equal(v.Key, attr) {
 			return v.Val, nil
 		}
+		if v.Namespace != "" && namespaced == nil &&
+			(equal(v.Namespace+":"+v.Key, attr) || equal(v.Key, attr)) {
+			namespaced = &node.Attr[i]
+		}
+	}
+	if namespaced != nil {
+		return namespaced.Val, nil
 	}
 	return "", AttributeNotFoundErr{Attr: attr}
 }
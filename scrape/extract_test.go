@@ -40,6 +40,24 @@ func TestExtractAttribute(t *testing.T) {
 			"",
 			AttributeNotFoundErr{Attr: "source"},
 		},
+		{
+			"@namespaced attr",
+			&html.Node{
+				Attr: []html.Attribute{{Namespace: "xlink", Key: "href", Val: "#icon"}, {Namespace: "", Key: "href", Val: "/"}},
+			},
+			"xlink:href",
+			"#icon",
+			nil,
+		},
+		{
+			"@attr without namespace first",
+			&html.Node{
+				Attr: []html.Attribute{{Namespace: "xlink", Key: "href", Val: "#icon"}, {Namespace: "", Key: "href", Val: "/"}},
+			},
+			"href",
+			"/",
+			nil,
+		},
 		{
 			"@attr source",
 			&html.Node{
@@ -65,3 +83,13 @@ func TestExtractAttribute(t *testing.T) {
 	}
 	tab.RunWithArgs(t, args, test)
 }
+
+func TestExtractAttributeFold(t *testing.T) {
+	doc := getDoc(`<svg viewBox="0 0 10 10"></svg>`)
+	node := doc.Find("svg").Nodes[0]
+	_, err := ExtractAttribute(node, "viewbox")
+	assert.Error(t, err)
+	act, err := ExtractAttributeFold(node, "VIEWBOX")
+	assert.NoError(t, err)
+	assert.Equal(t, "0 0 10 10", act)
+}
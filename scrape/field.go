@@ -0,0 +1,366 @@
+package scrape
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// field describes how to scrape a single value: where its nodes are,
+// how to extract data from them, and the rest of the tag options.
+type field struct {
+	selector string
+	extract  string
+	tag      reflect.StructTag
+
+	// grouped reports whether the selection is a group of sibling nodes
+	// (see [GroupTag]), so the selector matches the nodes of the group
+	// as well as their descendants.
+	grouped bool
+
+	// depth is the number of structs the field is nested in.
+	depth int
+
+	// position is the index of the element of the nearest scraped slice
+	// or array that the field belongs to.
+	position int
+
+	// sf is the struct field of the value, if the value is a struct field
+	// or an element of one.
+	// Its Index is the sequence of indexes from the struct that is scraped,
+	// which is longer than one for the fields of embedded structs.
+	sf reflect.StructField
+
+	// format describes how the extracted data is converted into the value.
+	format format
+
+	// where keeps the items of a slice or an array (see [WhereTag]), and
+	// whereErr is an error of parsing its clause.
+	where    predicate
+	whereErr error
+
+	// rules are the validation rules of the field (see [ValidateTag]),
+	// and rulesErr is an error of parsing them.
+	rules    []rule
+	rulesErr error
+}
+
+// newField creates a field from the tags of the given struct field.
+// If the field has no selector tag, the root selector declared by its
+// type is used (see [Rooted]). If the field has no extract tag, but has
+// the [DataTag], the data-* attribute is extracted.
+func newField(sf reflect.StructField) field {
+	selector, ok := sf.Tag.Lookup(SelectorTag)
+	if !ok {
+		selector = rootSelector(sf.Type)
+	}
+	extract, ok := sf.Tag.Lookup(ExtractorTag)
+	if data := sf.Tag.Get(DataTag); !ok && data != "" {
+		extract = AttrExtractTag + "data-" + data
+	}
+	var where predicate
+	var whereErr error
+	if clause, ok := sf.Tag.Lookup(WhereTag); ok {
+		where, whereErr = parseWhere(clause)
+	}
+	rules, err := parseRules(sf.Tag)
+	return field{
+		selector: selector,
+		extract:  extract,
+		tag:      sf.Tag,
+		sf:       sf,
+		format:   newFormat(sf.Tag),
+		where:    where,
+		whereErr: whereErr,
+		rules:    rules,
+		rulesErr: err,
+	}
+}
+
+// format describes how the extracted data is converted into numbers
+// and times. It is shared by the elements of slices, arrays and pointers.
+type format struct {
+	// number reports whether the value is a number written with the
+	// separators of the locale (see [NumberTag]).
+	number bool
+	locale string
+
+	// layouts are the layouts of times (see [TimeTag]), and location is
+	// the location of the times without a time zone (see [TimezoneTag]).
+	layouts  []string
+	location *time.Location
+
+	// enum maps the extracted data onto the values (see [EnumTag]).
+	enum []enumCase
+
+	// err is an error of parsing the tags.
+	err error
+}
+
+// newFormat creates a format from the tags of a struct field.
+func newFormat(tag reflect.StructTag) format {
+	fm := format{}
+	fm.locale, fm.number = tag.Lookup(NumberTag)
+	if layouts := tag.Get(TimeTag); layouts != "" {
+		fm.layouts = strings.Split(layouts, TimeLayoutSeparator)
+	}
+	if timezone := tag.Get(TimezoneTag); timezone != "" {
+		fm.location, fm.err = time.LoadLocation(timezone)
+	}
+	if enum := tag.Get(EnumTag); enum != "" && fm.err == nil {
+		fm.enum, fm.err = parseEnum(enum)
+	}
+	return fm
+}
+
+// value returns the struct field of ov described by the field. Nil
+// pointers to embedded structs on the way to it are allocated.
+func (f field) value(ov reflect.Value) reflect.Value {
+	for i, x := range f.sf.Index {
+		if i > 0 && ov.Kind() == reflect.Pointer {
+			if ov.IsNil() {
+				ov.Set(reflect.New(ov.Type().Elem()))
+			}
+			ov = ov.Elem()
+		}
+		ov = ov.Field(x)
+	}
+	return ov
+}
+
+// Rooted is implemented by types that declare their default root selector,
+// which is used when the type is scraped without a selector. A struct type
+// can also declare it with a blank field:
+//
+//	type Product struct {
+//		_    struct{} `select:".product"`
+//		Name string   `select:"h2" extract:"text"`
+//	}
+type Rooted interface {
+	RootSelector() string
+}
+
+// rootSelector returns the root selector declared by the given type, the
+// element type of a slice, or the type a pointer points to.
+func rootSelector(ot reflect.Type) string {
+	for ot.Kind() == reflect.Pointer || ot.Kind() == reflect.Slice {
+		ot = ot.Elem()
+	}
+	if reflect.PointerTo(ot).Implements(reflect.TypeFor[Rooted]()) {
+		return reflect.New(ot).Interface().(Rooted).RootSelector()
+	}
+	if ot.Kind() != reflect.Struct {
+		return ""
+	}
+	for i := 0; i < ot.NumField(); i++ {
+		if sf := ot.Field(i); sf.Name == "_" {
+			return sf.Tag.Get(SelectorTag)
+		}
+	}
+	return ""
+}
+
+// plans caches the fields of struct types. A plan of a type is built from
+// the tags of its own fields and embedded structs only, so self-referential
+// types are safe.
+var plans sync.Map // map[reflect.Type][]field
+
+// structFields returns the fields of the given struct type in the order
+// of their declaration. Unexported fields and fields with the [SkipSelector]
+// are skipped. The fields of embedded structs without a selector tag are
+// promoted, unless they are hidden by a shallower field with the same name.
+func structFields(ot reflect.Type) []field {
+	if fs, ok := plans.Load(ot); ok {
+		return fs.([]field)
+	}
+	sfs := promotedFields(ot, nil, map[reflect.Type]bool{})
+	depths := map[string]int{}
+	for _, sf := range sfs {
+		if d, ok := depths[sf.Name]; !ok || len(sf.Index) < d {
+			depths[sf.Name] = len(sf.Index)
+		}
+	}
+	fs := []field{}
+	for _, sf := range sfs {
+		if len(sf.Index) == depths[sf.Name] {
+			fs = append(fs, newField(sf))
+		}
+	}
+	plans.Store(ot, fs)
+	return fs
+}
+
+// promotedFields returns the fields of the struct type with the fields of
+// its embedded structs in place of them.
+func promotedFields(ot reflect.Type, index []int, visited map[reflect.Type]bool) []reflect.StructField {
+	visited[ot] = true
+	defer delete(visited, ot)
+
+	sfs := []reflect.StructField{}
+	for i := 0; i < ot.NumField(); i++ {
+		sf := ot.Field(i)
+		sf.Index = append(append([]int{}, index...), i)
+		if sf.Tag.Get(SelectorTag) == SkipSelector {
+			continue
+		}
+		if et := embeddedStruct(sf); et != nil && !visited[et] {
+			sfs = append(sfs, promotedFields(et, sf.Index, visited)...)
+			continue
+		}
+		if sf.IsExported() {
+			sfs = append(sfs, sf)
+		}
+	}
+	return sfs
+}
+
+// embeddedStruct returns the type of the embedded struct that the field
+// holds directly or by a pointer, if the field has no selector tag. An
+// unexported struct is promoted only if it is held directly, because
+// a pointer to it cannot be set.
+func embeddedStruct(sf reflect.StructField) reflect.Type {
+	if _, ok := sf.Tag.Lookup(SelectorTag); !sf.Anonymous || ok {
+		return nil
+	}
+	et := sf.Type
+	if et.Kind() == reflect.Pointer {
+		if !sf.IsExported() {
+			return nil
+		}
+		et = et.Elem()
+	}
+	if et.Kind() != reflect.Struct {
+		return nil
+	}
+	return et
+}
+
+// find returns the nodes of the selection matched by the field selector.
+// If the selector is empty the selection is returned as is.
+func (f field) find(ft *foreignTree, selection *goquery.Selection) *goquery.Selection {
+	if len(f.selector) == 0 {
+		return selection
+	}
+	if isRelativeSelector(f.selector) {
+		return findRelative(ft, selection, f.selector)
+	}
+	if !f.grouped {
+		return findSelector(ft, selection, f.selector)
+	}
+	if f.selector == HeadingSelector {
+		return selection.First()
+	}
+	found := emptySelection(selection)
+	selection.Each(func(i int, s *goquery.Selection) {
+		found = found.AddSelection(filterSelector(ft, s, f.selector)).AddSelection(findSelector(ft, s, f.selector))
+	})
+	return found
+}
+
+// level splits the selector of the field into the selector of this nesting
+// level and the selectors of the deeper ones (see [LevelSeparator]), and
+// returns the field of this level and the field of its elements. The
+// separators in quotes, brackets, and parentheses are not split.
+func (f field) level() (field, field) {
+	levels := splitOutside(f.selector, LevelSeparator[0])
+	f.selector = strings.TrimSpace(levels[0])
+	fe := field{
+		selector: strings.TrimSpace(strings.Join(levels[1:], LevelSeparator)),
+		extract:  f.extract,
+		grouped:  f.tag.Get(GroupTag) != "",
+		depth:    f.depth,
+		sf:       f.sf,
+		format:   f.format,
+	}
+	return f, fe
+}
+
+// elem returns the field of the value that a pointer or an interface of
+// the field holds. The value is scraped from the already found nodes.
+func (f field) elem() field {
+	return field{
+		extract:  f.extract,
+		tag:      f.tag,
+		grouped:  f.grouped && f.selector == "",
+		depth:    f.depth,
+		position: f.position,
+		sf:       f.sf,
+		format:   f.format,
+	}
+}
+
+// items returns the nodes of the selection matched by the field selector
+// one by one, or the groups of the children of every matched node if the
+// field has the [GroupTag]. Only the items matching the [WhereTag] are
+// returned. A relative selector that matches nothing has no items, so the
+// leaves of recursive types get empty slices.
+func (f field) items(sc *scraping, selection *goquery.Selection) ([]*goquery.Selection, error) {
+	selection = f.find(sc.foreign, selection)
+
+	if selection.Size() == 0 && isRelativeSelector(f.selector) {
+		return []*goquery.Selection{}, nil
+	}
+	if selection.Size() == 0 {
+		return nil, ScrapingErr{Selector: f.selector, Cause: NoNodesFoundErr{}}
+	}
+
+	if f.whereErr != nil {
+		return nil, ScrapingErr{Selector: f.selector, Cause: f.whereErr}
+	}
+
+	items := []*goquery.Selection{}
+	group := f.tag.Get(GroupTag)
+	if group != "" {
+		selection.Each(func(i int, selection *goquery.Selection) {
+			items = append(items, groupSiblings(sc.foreign, selection.Children(), group)...)
+		})
+		if len(items) == 0 {
+			return nil, ScrapingErr{Selector: f.selector, Cause: NoNodesFoundErr{}}
+		}
+	} else {
+		selection.Each(func(i int, selection *goquery.Selection) {
+			items = append(items, selection)
+		})
+	}
+
+	if f.where != nil {
+		items = slices.DeleteFunc(items, func(item *goquery.Selection) bool { return !f.where(sc, item, group != "") })
+	}
+	return items, nil
+}
+
+// checkSize checks the number of nodes found for an array of the given
+// length against the [OverflowTag] and [UnderflowTag] policies.
+func (f field) checkSize(length, found int) error {
+	if (found > length && f.tag.Get(OverflowTag) == ErrorPolicy) ||
+		(found < length && f.tag.Get(UnderflowTag) == ErrorPolicy) {
+		return ArraySizeErr{Len: length, Found: found}
+	}
+	return nil
+}
+
+// groupSiblings splits the given sibling nodes into groups. Every group
+// starts with a node matched by the selector and contains all the following
+// siblings up to the next matched node. The nodes before the first matched
+// node are skipped.
+func groupSiblings(ft *foreignTree, siblings *goquery.Selection, selector string) []*goquery.Selection {
+	groups := []*goquery.Selection{}
+	start := -1
+	siblings.Each(func(i int, s *goquery.Selection) {
+		if filterSelector(ft, s, selector).Size() == 0 {
+			return
+		}
+		if start >= 0 {
+			groups = append(groups, siblings.Slice(start, i))
+		}
+		start = i
+	})
+	if start >= 0 {
+		groups = append(groups, siblings.Slice(start, siblings.Size()))
+	}
+	return groups
+}
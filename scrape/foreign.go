@@ -0,0 +1,140 @@
+package scrape
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/andybalholm/cascadia"
+	"golang.org/x/net/html"
+)
+
+// camelCaseTags are the SVG elements whose names keep their case in the
+// parsed document, while type selectors are always matched in lower case.
+var camelCaseTags = []string{"altglyph", "altglyphdef", "altglyphitem",
+	"animatecolor", "animatemotion", "animatetransform", "clippath", "feblend",
+	"fecolormatrix", "fecomponenttransfer", "fecomposite", "feconvolvematrix",
+	"fediffuselighting", "fedisplacementmap", "fedistantlight", "feflood",
+	"fefunca", "fefuncb", "fefuncg", "fefuncr", "fegaussianblur", "feimage",
+	"femerge", "femergenode", "femorphology", "feoffset", "fepointlight",
+	"fespecularlighting", "fespotlight", "fetile", "feturbulence",
+	"foreignobject", "glyphref", "lineargradient", "radialgradient", "textpath"}
+
+// foreignTagRegexp matches the camel case element names as whole words of
+// a selector, so it skips classes and ids (".clippath-icon").
+var foreignTagRegexp = regexp.MustCompile(`(?i)(?:^|[^\w.#\\-])(?:` +
+	strings.Join(camelCaseTags, "|") + `)(?:$|[^\w-])`)
+
+// foreignAttrRegexp matches an attribute selector with an escaped colon.
+var foreignAttrRegexp = regexp.MustCompile(`\[[^\]]*\\:`)
+
+// isForeignSelector reports whether the selector targets foreign content
+// (SVG and MathML) in a way the selector engine does not support: by a
+// camel case element name ("linearGradient") or by a namespace-qualified
+// attribute with an escaped colon ("[xlink\:href]").
+func isForeignSelector(selector string) bool {
+	return foreignAttrRegexp.MatchString(selector) || foreignTagRegexp.MatchString(selector)
+}
+
+// findSelector returns the descendants of the selection matched by the
+// selector, including the ones in foreign content, which is matched
+// against the given tree. If the tree is nil, a new one is built.
+func findSelector(ft *foreignTree, selection *goquery.Selection, selector string) *goquery.Selection {
+	if !isForeignSelector(selector) {
+		return selection.Find(selector)
+	}
+	if ft == nil {
+		ft = newForeignTree()
+	}
+	return ft.find(selection, selector)
+}
+
+// filterSelector returns the nodes of the selection matched by the
+// selector, including the ones in foreign content, which is matched
+// against the given tree. If the tree is nil, a new one is built.
+func filterSelector(ft *foreignTree, selection *goquery.Selection, selector string) *goquery.Selection {
+	if !isForeignSelector(selector) {
+		return selection.Filter(selector)
+	}
+	if ft == nil {
+		ft = newForeignTree()
+	}
+	return ft.filter(selection, selector)
+}
+
+// foreignTree is a copy of the trees of nodes where the names of foreign
+// elements are in lower case and the namespace-qualified attributes are
+// named with their namespaces ("xlink:href"), so that the selector engine
+// matches them. A tree is copied once and reused for the whole scraping.
+type foreignTree struct {
+	clones  map[*html.Node]*html.Node // copies of the original nodes
+	origins map[*html.Node]*html.Node // original nodes of the copies
+}
+
+func newForeignTree() *foreignTree {
+	return &foreignTree{clones: map[*html.Node]*html.Node{}, origins: map[*html.Node]*html.Node{}}
+}
+
+// find works as [goquery.Selection.Find] but matches the selector against
+// the copies of the nodes.
+func (t *foreignTree) find(selection *goquery.Selection, selector string) *goquery.Selection {
+	matcher, err := cascadia.Compile(selector)
+	if err != nil {
+		return emptySelection(selection)
+	}
+	found := []*html.Node{}
+	for _, node := range selection.Nodes {
+		for c := t.clone(node).FirstChild; c != nil; c = c.NextSibling {
+			for _, n := range matcher.MatchAll(c) {
+				found = append(found, t.origins[n])
+			}
+		}
+	}
+	return selection.FindNodes(found...)
+}
+
+// filter works as [goquery.Selection.Filter] but matches the selector
+// against the copies of the nodes.
+func (t *foreignTree) filter(selection *goquery.Selection, selector string) *goquery.Selection {
+	matcher, err := cascadia.Compile(selector)
+	if err != nil {
+		return emptySelection(selection)
+	}
+	return selection.FilterFunction(func(_ int, s *goquery.Selection) bool {
+		return matcher.Match(t.clone(s.Nodes[0]))
+	})
+}
+
+// clone returns the copy of the node. The whole tree of the node is copied
+// the first time, so that the selectors match the ancestors of the node.
+func (t *foreignTree) clone(node *html.Node) *html.Node {
+	if clone, ok := t.clones[node]; ok {
+		return clone
+	}
+	root := node
+	for root.Parent != nil {
+		root = root.Parent
+	}
+	t.copy(root)
+	return t.clones[node]
+}
+
+// copy copies the node and its descendants.
+func (t *foreignTree) copy(node *html.Node) *html.Node {
+	clone := &html.Node{Type: node.Type, DataAtom: node.DataAtom, Data: node.Data}
+	if node.Type == html.ElementNode && node.Namespace != "" {
+		clone.Data = strings.ToLower(node.Data)
+	}
+	for _, a := range node.Attr {
+		if a.Namespace != "" {
+			a.Key = a.Namespace + ":" + a.Key
+			a.Namespace = ""
+		}
+		clone.Attr = append(clone.Attr, a)
+	}
+	t.clones[node], t.origins[clone] = clone, node
+	for n := node.FirstChild; n != nil; n = n.NextSibling {
+		clone.AppendChild(t.copy(n))
+	}
+	return clone
+}
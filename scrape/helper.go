@@ -3,6 +3,8 @@ package scrape
 import (
 	"reflect"
 	"slices"
+	"strconv"
+	"strings"
 )
 
 // ValidateNotNil checks if the given o variable is nil or is a pointer
@@ -21,3 +23,55 @@ func ValidateNotNil(o any, varName string) error {
 	}
 	return nil
 }
+
+// setValue parses the given string according to the kind of ov and sets
+// the result to ov. ov must be a string, bool, integer, or float.
+func setValue(ov reflect.Value, val string) error {
+	switch ov.Kind() {
+	case reflect.String:
+		ov.SetString(val)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(strings.TrimSpace(val))
+		if err != nil {
+			return err
+		}
+		ov.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(strings.TrimSpace(val), 10, ov.Type().Bits())
+		if err != nil {
+			return err
+		}
+		ov.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(strings.TrimSpace(val), 10, ov.Type().Bits())
+		if err != nil {
+			return err
+		}
+		ov.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(strings.TrimSpace(val), ov.Type().Bits())
+		if err != nil {
+			return err
+		}
+		ov.SetFloat(f)
+	default:
+		return KindErr{Var: "value", KindExp: "string, bool, or number", KindAct: ov.Kind()}
+	}
+	return nil
+}
+
+// splitText splits the text by the separator, or by spaces if the separator
+// is empty, trims the parts, and drops the empty ones.
+func splitText(text string, sep string) []string {
+	parts := strings.Fields(text)
+	if sep != "" {
+		parts = strings.Split(text, sep)
+	}
+	trimmed := []string{}
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			trimmed = append(trimmed, part)
+		}
+	}
+	return trimmed
+}
@@ -0,0 +1,23 @@
+package scrape
+
+import "reflect"
+
+// AfterScraper is implemented by types that need to do some work after
+// all their fields are scraped, for instance, to compute fields that are
+// not scraped (select:"-") from the scraped ones. The method is called
+// on a pointer to the value.
+type AfterScraper interface {
+	AfterScrape() error
+}
+
+// afterScrape calls the AfterScrape method of the given value if it
+// implements [AfterScraper].
+func afterScrape(ov reflect.Value) error {
+	if !ov.CanAddr() {
+		return nil
+	}
+	if as, ok := ov.Addr().Interface().(AfterScraper); ok {
+		return as.AfterScrape()
+	}
+	return nil
+}
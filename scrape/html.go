@@ -0,0 +1,126 @@
+package scrape
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// ExtractInnerHTML returns the HTML of the children of the node.
+func ExtractInnerHTML(node *html.Node) (string, error) {
+	buf := bytes.Buffer{}
+	for n := node.FirstChild; n != nil; n = n.NextSibling {
+		if err := html.Render(&buf, n); err != nil {
+			return "", err
+		}
+	}
+	return buf.String(), nil
+}
+
+// ExtractOuterHTML returns the HTML of the node itself.
+func ExtractOuterHTML(node *html.Node) (string, error) {
+	buf := bytes.Buffer{}
+	if err := html.Render(&buf, node); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// Sanitizer renders HTML keeping only the allowed elements and attributes.
+// The elements that are not allowed are replaced by their children, except
+// the elements whose content is never safe (script, style, and others),
+// which are dropped with all their descendants. The URLs with schemes other
+// than http, https, and mailto are dropped too.
+type Sanitizer struct {
+	// Elements maps names of the allowed elements to names of their
+	// allowed attributes.
+	Elements map[string][]string
+}
+
+// DefaultSanitizer returns a Sanitizer that allows text formatting,
+// lists, tables, links, and images.
+func DefaultSanitizer() Sanitizer {
+	return Sanitizer{Elements: map[string][]string{
+		"p": nil, "br": nil, "hr": nil, "div": nil, "span": nil,
+		"h1": nil, "h2": nil, "h3": nil, "h4": nil, "h5": nil, "h6": nil,
+		"b": nil, "strong": nil, "i": nil, "em": nil, "u": nil, "s": nil,
+		"sub": nil, "sup": nil, "small": nil, "mark": nil,
+		"code": nil, "pre": nil, "blockquote": nil,
+		"ul": nil, "ol": nil, "li": nil, "dl": nil, "dt": nil, "dd": nil,
+		"table": nil, "thead": nil, "tbody": nil, "tfoot": nil, "tr": nil,
+		"th": {"colspan", "rowspan"}, "td": {"colspan", "rowspan"},
+		"a":   {"href", "title"},
+		"img": {"src", "alt", "title", "width", "height"},
+	}}
+}
+
+// unsafeElements are the elements dropped with all their descendants.
+var unsafeElements = []string{"script", "style", "template", "noscript",
+	"iframe", "object", "embed", "svg", "math", "head", "title"}
+
+// urlAttributes are the attributes that hold URLs.
+var urlAttributes = []string{"href", "src", "action", "formaction", "poster", "cite"}
+
+// Sanitize returns the sanitized HTML of the children of the node.
+func (s Sanitizer) Sanitize(node *html.Node) (string, error) {
+	root := &html.Node{Type: html.DocumentNode}
+	for n := node.FirstChild; n != nil; n = n.NextSibling {
+		s.appendSanitized(root, n)
+	}
+	return ExtractInnerHTML(root)
+}
+
+// appendSanitized appends the sanitized copy of the node to the parent.
+func (s Sanitizer) appendSanitized(parent, node *html.Node) {
+	switch node.Type {
+	case html.TextNode:
+		parent.AppendChild(&html.Node{Type: html.TextNode, Data: node.Data})
+		return
+	case html.ElementNode:
+	default:
+		return
+	}
+
+	if slices.Contains(unsafeElements, node.Data) {
+		return
+	}
+
+	attrs, ok := s.Elements[node.Data]
+	if ok {
+		clone := &html.Node{Type: html.ElementNode, Data: node.Data, DataAtom: node.DataAtom}
+		for _, a := range node.Attr {
+			if a.Namespace == "" && slices.Contains(attrs, a.Key) && safeAttribute(a) {
+				clone.Attr = append(clone.Attr, html.Attribute{Key: a.Key, Val: a.Val})
+			}
+		}
+		parent.AppendChild(clone)
+		parent = clone
+	}
+
+	for n := node.FirstChild; n != nil; n = n.NextSibling {
+		s.appendSanitized(parent, n)
+	}
+}
+
+// safeAttribute reports whether the value of the attribute is safe. Only
+// the URLs of the http, https, and mailto schemes and relative ones are
+// considered safe.
+func safeAttribute(a html.Attribute) bool {
+	if !slices.Contains(urlAttributes, a.Key) {
+		return true
+	}
+	// browsers ignore whitespace and control characters in URL schemes
+	val := strings.Map(func(r rune) rune {
+		if r <= ' ' {
+			return -1
+		}
+		return r
+	}, strings.ToLower(a.Val))
+	scheme, _, ok := strings.Cut(val, ":")
+	if !ok || strings.ContainsAny(scheme, "/?#") {
+		return true
+	}
+	return scheme == "http" || scheme == "https" || scheme == "mailto"
+}
@@ -0,0 +1,53 @@
+package scrape_test
+
+import (
+	"testing"
+
+	. "github.com/branow/htmlscraper/scrape"
+	"github.com/branow/tabtest/tab"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractInnerHTML(t *testing.T) {
+	doc := getDoc(`<div class="x"><b>Bold</b> &amp; <i>italic</i></div>`)
+	act, err := ExtractInnerHTML(doc.Find(".x").Nodes[0])
+	assert.NoError(t, err)
+	assert.Equal(t, "<b>Bold</b> &amp; <i>italic</i>", act)
+}
+
+func TestExtractOuterHTML(t *testing.T) {
+	doc := getDoc(`<div class="x"><b>Bold</b></div>`)
+	act, err := ExtractOuterHTML(doc.Find(".x").Nodes[0])
+	assert.NoError(t, err)
+	assert.Equal(t, `<div class="x"><b>Bold</b></div>`, act)
+}
+
+func TestSanitizer_Sanitize(t *testing.T) {
+	args := []tab.Args{
+		{
+			"@default",
+			DefaultSanitizer(),
+			`<p onclick="x()">Text <a href="/p/1" target="_blank">link</a><script>alert(1)</script></p>`,
+			`<p>Text <a href="/p/1">link</a></p>`,
+		},
+		{
+			"@unsafe url",
+			DefaultSanitizer(),
+			`<a href="java&#09;script:alert(1)">link</a><img src="https://site.com/a.png">`,
+			`<a>link</a><img src="https://site.com/a.png"/>`,
+		},
+		{
+			"@custom allowlist",
+			Sanitizer{Elements: map[string][]string{"b": nil}},
+			`<p><b class="x">Bold</b> <i>italic</i></p><style>p {}</style>`,
+			`<b>Bold</b> italic`,
+		},
+	}
+	test := func(t *testing.T, s Sanitizer, data, exp string) {
+		doc := getDoc(`<div class="x">` + data + `</div>`)
+		act, err := s.Sanitize(doc.Find(".x").Nodes[0])
+		assert.NoError(t, err)
+		assert.Equal(t, exp, act)
+	}
+	tab.RunWithArgs(t, args, test)
+}
@@ -0,0 +1,181 @@
+package scrape
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/html"
+)
+
+// ImageCandidate is a source of an image that a browser can choose from.
+type ImageCandidate struct {
+	URL     string
+	Width   int     // width descriptor of the srcset ("640w"), zero if absent
+	Density float64 // pixel density descriptor of the srcset ("2x"), zero if absent
+}
+
+// srcsetAttributes are the attributes that hold sets of image sources,
+// including the ones used by lazy-loading scripts.
+var srcsetAttributes = []string{"data-srcset", "data-lazy-srcset", "srcset"}
+
+// srcAttributes are the attributes that hold an image source, including
+// the ones used by lazy-loading scripts.
+var srcAttributes = []string{"data-src", "data-lazy-src", "data-original", "data-lazy", "src"}
+
+// ExtractImages returns the image candidates of the node. The node can be
+// an <img>, a <picture>, or an element with one of them inside. Candidates
+// are taken from the srcset of the <source> elements of a picture and from
+// the srcset and src attributes of an img, including the attributes used by
+// lazy-loading scripts (data-src, data-srcset, and others). Inline data URIs,
+// which are usually placeholders, are skipped. The sizes attribute is not
+// read: it depends on the viewport, which a document does not have, so the
+// width descriptors are returned as they are.
+func ExtractImages(node *html.Node) []ImageCandidate {
+	img := findImage(node)
+	if img == nil {
+		return nil
+	}
+
+	candidates := []ImageCandidate{}
+	if img.Parent != nil && img.Parent.Data == "picture" {
+		for n := img.Parent.FirstChild; n != nil; n = n.NextSibling {
+			if n.Type == html.ElementNode && n.Data == "source" {
+				candidates = append(candidates, srcsetCandidates(n)...)
+			}
+		}
+	}
+	candidates = append(candidates, srcsetCandidates(img)...)
+	for _, attr := range srcAttributes {
+		if src, err := ExtractAttribute(img, attr); err == nil && strings.TrimSpace(src) != "" {
+			candidates = append(candidates, ImageCandidate{URL: strings.TrimSpace(src)})
+		}
+	}
+
+	unique := []ImageCandidate{}
+	for _, c := range candidates {
+		dup := slices.ContainsFunc(unique, func(u ImageCandidate) bool { return u.URL == c.URL })
+		if !dup && !strings.HasPrefix(strings.ToLower(c.URL), "data:") {
+			unique = append(unique, c)
+		}
+	}
+	return unique
+}
+
+// ExtractImage returns the URL of the best image candidate of the node (see
+// [ExtractImages]): the widest one, or the one with the highest density if
+// there are no width descriptors. The sizes attribute is ignored, so the
+// best candidate is the one for the widest viewport. The URL is not resolved;
+// the [ImageExtractTag] extractor resolves it against the base URL of the
+// page (see [Scraper.BaseURL]).
+func ExtractImage(node *html.Node) (string, error) {
+	candidates := ExtractImages(node)
+	if len(candidates) == 0 {
+		return "", ImageNotFoundErr{}
+	}
+	best := slices.MaxFunc(candidates, func(a, b ImageCandidate) int {
+		if c := cmp.Compare(a.Width, b.Width); c != 0 {
+			return c
+		}
+		return cmp.Compare(density(a), density(b))
+	})
+	return best.URL, nil
+}
+
+// density returns the pixel density of the candidate, which is 1 by default.
+func density(c ImageCandidate) float64 {
+	if c.Density == 0 && c.Width == 0 {
+		return 1
+	}
+	return c.Density
+}
+
+// findImage returns the img element of the node: the node itself, the img
+// of a picture, or the first img among the descendants.
+func findImage(node *html.Node) *html.Node {
+	if node.Type == html.ElementNode && node.Data == "img" {
+		return node
+	}
+	for n := node.FirstChild; n != nil; n = n.NextSibling {
+		if img := findImage(n); img != nil {
+			return img
+		}
+	}
+	return nil
+}
+
+// srcsetCandidates returns the candidates of the first srcset attribute
+// of the node.
+func srcsetCandidates(node *html.Node) []ImageCandidate {
+	for _, attr := range srcsetAttributes {
+		if srcset, err := ExtractAttribute(node, attr); err == nil && strings.TrimSpace(srcset) != "" {
+			return ParseSrcset(srcset)
+		}
+	}
+	return nil
+}
+
+// ParseSrcset parses the value of a srcset attribute into image candidates.
+// Candidates with invalid descriptors are skipped.
+func ParseSrcset(srcset string) []ImageCandidate {
+	candidates := []ImageCandidate{}
+	rest := srcset
+	for {
+		rest = strings.TrimLeftFunc(rest, func(r rune) bool { return unicode.IsSpace(r) || r == ',' })
+		if rest == "" {
+			return candidates
+		}
+
+		end := strings.IndexFunc(rest, unicode.IsSpace)
+		if end < 0 {
+			end = len(rest)
+		}
+		u := rest[:end]
+		rest = rest[end:]
+
+		descriptors := ""
+		if trimmed := strings.TrimRight(u, ","); trimmed != u {
+			u = trimmed
+		} else {
+			comma := strings.IndexRune(rest, ',')
+			if comma < 0 {
+				comma = len(rest)
+			}
+			descriptors, rest = rest[:comma], rest[comma:]
+		}
+
+		c, ok := parseDescriptors(u, strings.Fields(descriptors))
+		if ok {
+			candidates = append(candidates, c)
+		}
+	}
+}
+
+func parseDescriptors(u string, descriptors []string) (ImageCandidate, bool) {
+	c := ImageCandidate{URL: u}
+	for _, d := range descriptors {
+		if len(d) < 2 {
+			return c, false
+		}
+		value := d[:len(d)-1]
+		switch d[len(d)-1] {
+		case 'w':
+			w, err := strconv.Atoi(value)
+			if err != nil || w <= 0 {
+				return c, false
+			}
+			c.Width = w
+		case 'x':
+			x, err := strconv.ParseFloat(value, 64)
+			if err != nil || x <= 0 {
+				return c, false
+			}
+			c.Density = x
+		default:
+			return c, false
+		}
+	}
+	return c, true
+}
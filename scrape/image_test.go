@@ -0,0 +1,79 @@
+package scrape_test
+
+import (
+	"testing"
+
+	. "github.com/branow/htmlscraper/scrape"
+	"github.com/branow/tabtest/tab"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSrcset(t *testing.T) {
+	args := []tab.Args{
+		{
+			"@widths",
+			"a-320.jpg 320w, a-640.jpg 640w",
+			[]ImageCandidate{{URL: "a-320.jpg", Width: 320}, {URL: "a-640.jpg", Width: 640}},
+		},
+		{
+			"@densities and commas in urls",
+			"https://cdn.com/w_100,h_100/a.jpg, https://cdn.com/w_200,h_200/a.jpg 2x",
+			[]ImageCandidate{{URL: "https://cdn.com/w_100,h_100/a.jpg"}, {URL: "https://cdn.com/w_200,h_200/a.jpg", Density: 2}},
+		},
+		{
+			"@invalid descriptor",
+			"a.jpg 12q, b.jpg 1.5x",
+			[]ImageCandidate{{URL: "b.jpg", Density: 1.5}},
+		},
+	}
+	test := func(t *testing.T, srcset string, exp []ImageCandidate) {
+		assert.Equal(t, exp, ParseSrcset(srcset))
+	}
+	tab.RunWithArgs(t, args, test)
+}
+
+func TestExtractImage(t *testing.T) {
+	args := []tab.Args{
+		{
+			"@lazy image",
+			`<img src="data:image/gif;base64,R0lGOD" data-src="real.jpg">`,
+			"real.jpg",
+			nil,
+		},
+		{
+			"@widest",
+			`<img src="small.jpg" srcset="medium.jpg 640w, large.jpg 1280w">`,
+			"large.jpg",
+			nil,
+		},
+		{
+			"@densest",
+			`<img src="small.jpg" srcset="double.jpg 2x">`,
+			"double.jpg",
+			nil,
+		},
+		{
+			"@picture",
+			`<picture><source type="image/webp" srcset="a.webp 800w, b.webp 1600w"><img src="a.jpg"></picture>`,
+			"b.webp",
+			nil,
+		},
+		{
+			"@no image",
+			`<span>text</span>`,
+			"",
+			ImageNotFoundErr{},
+		},
+	}
+	test := func(t *testing.T, data string, exp string, eErr error) {
+		doc := getDoc(`<div class="x">` + data + `</div>`)
+		act, aErr := ExtractImage(doc.Find(".x").Nodes[0])
+		if eErr == nil {
+			assert.NoError(t, aErr)
+		} else if assert.Error(t, aErr) {
+			assert.EqualError(t, aErr, eErr.Error())
+		}
+		assert.Equal(t, exp, act)
+	}
+	tab.RunWithArgs(t, args, test)
+}
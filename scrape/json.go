@@ -0,0 +1,251 @@
+package scrape
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// JSONPathTag is a tag that decodes the field from the JSON embedded in the
+// node (see [ExtractJSON]), or extracted by the extract tag, instead of
+// scraping it: jsonpath:"props.pageProps.product.price". The path is a
+// sequence of object keys and array indexes separated by dots ("items.0.name"
+// or "items[0].name"); an empty path decodes the whole JSON. The value at the
+// path is decoded into the field with [json.Unmarshal], so the field can be
+// of any type the JSON fits, and a string field gets the JSON text of
+// a value that is not a string.
+const JSONPathTag = "jsonpath"
+
+// ExtractJSON returns the JSON embedded in the node, which is usually a
+// <script type="application/json">, a <script id="__NEXT_DATA__">, or
+// a script assigning an object literal ("window.__STATE__ = {...};").
+// Object literals may use JavaScript syntax: unquoted keys, single-quoted
+// strings, trailing commas, and comments.
+func ExtractJSON(node *html.Node) (string, error) {
+	text := strings.TrimSpace(ExtractDeepText(node))
+	if json.Valid([]byte(text)) {
+		return text, nil
+	}
+
+	literal := findLiteral(text)
+	if literal == "" {
+		return "", JSONNotFoundErr{}
+	}
+	if json.Valid([]byte(literal)) {
+		return literal, nil
+	}
+	if converted := jsToJSON(literal); json.Valid([]byte(converted)) {
+		return converted, nil
+	}
+	return "", JSONNotFoundErr{}
+}
+
+// assignmentRegexp matches an assignment of an object or array literal.
+var assignmentRegexp = regexp.MustCompile(`=\s*[{\[]`)
+
+// findLiteral returns the object or array literal assigned in the script,
+// or the first literal of the script if there is no assignment.
+func findLiteral(script string) string {
+	start := strings.IndexAny(script, "{[")
+	if loc := assignmentRegexp.FindStringIndex(script); loc != nil {
+		start = loc[1] - 1
+	}
+	if start < 0 {
+		return ""
+	}
+
+	depth, quote := 0, byte(0)
+	for i := start; i < len(script); i++ {
+		c := script[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'' || c == '`':
+			quote = c
+		case c == '{' || c == '[':
+			depth++
+		case c == '}' || c == ']':
+			depth--
+			if depth == 0 {
+				return script[start : i+1]
+			}
+		}
+	}
+	return ""
+}
+
+// jsToJSON converts a JavaScript object literal into JSON: it quotes
+// the keys, turns single-quoted strings into double-quoted ones, drops
+// trailing commas and comments, and replaces undefined with null.
+func jsToJSON(literal string) string {
+	out := strings.Builder{}
+	for i := 0; i < len(literal); i++ {
+		c := literal[i]
+		switch {
+		case c == '"' || c == '\'':
+			end := i + 1
+			for end < len(literal) && literal[end] != c {
+				if literal[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			s := literal[i+1 : min(end, len(literal))]
+			if c == '\'' {
+				s = strings.ReplaceAll(strings.ReplaceAll(s, `\'`, `'`), `"`, `\"`)
+			}
+			out.WriteString(`"` + s + `"`)
+			i = end
+		case c == '/' && strings.HasPrefix(literal[i:], "//"):
+			end := strings.IndexByte(literal[i:], '\n')
+			if end < 0 {
+				return out.String()
+			}
+			i += end
+		case c == '/' && strings.HasPrefix(literal[i:], "/*"):
+			end := strings.Index(literal[i+2:], "*/")
+			if end < 0 {
+				return out.String()
+			}
+			i += end + 3
+		case c == ',':
+			next := strings.TrimLeft(literal[i+1:], " \t\r\n")
+			if next == "" || next[0] == '}' || next[0] == ']' {
+				continue
+			}
+			out.WriteByte(c)
+		case c == '_' || c == '$' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z'):
+			end := i + 1
+			for end < len(literal) && isIdentByte(literal[end]) {
+				end++
+			}
+			ident := literal[i:end]
+			next := strings.TrimLeft(literal[end:], " \t\r\n")
+			switch {
+			case strings.HasPrefix(next, ":"):
+				out.WriteString(strconv.Quote(ident))
+			case ident == "undefined":
+				out.WriteString("null")
+			default:
+				out.WriteString(ident)
+			}
+			i = end - 1
+		default:
+			out.WriteByte(c)
+		}
+	}
+	return out.String()
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || c == '$' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
+
+// scrapeJSON decodes the value at the JSON path of the field (see
+// [JSONPathTag]) from the JSON of the first node matched by the field
+// selector.
+func (scraper Scraper) scrapeJSON(sc *scraping, selection *goquery.Selection, ot reflect.Type, ov reflect.Value, f field, path string) error {
+	var data any
+	var err error
+	if f.extract == "" {
+		selection = f.find(sc.foreign, selection)
+		if selection.Size() == 0 {
+			return ScrapingErr{Selector: f.selector, Cause: NoNodesFoundErr{}}
+		}
+		data, err = sc.nodeJSON(selection.Nodes[0])
+	} else {
+		var val string
+		val, err = scraper.extractValue(sc, selection, ot, f)
+		if err != nil {
+			return err
+		}
+		data, err = decodeJSON(val)
+	}
+	if err != nil {
+		return ScrapingErr{Selector: f.selector, Cause: err}
+	}
+
+	data, err = jsonAt(data, path)
+	if err != nil {
+		return ScrapingErr{Selector: f.selector, Cause: err}
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return ScrapingErr{Selector: f.selector, Cause: err}
+	}
+	vp := reflect.New(ot)
+	if _, ok := data.(string); ot.Kind() == reflect.String && !ok && data != nil {
+		vp.Elem().SetString(string(raw))
+	} else if err := json.Unmarshal(raw, vp.Interface()); err != nil {
+		return ScrapingErr{Selector: f.selector, Cause: err}
+	}
+	ov.Set(vp.Elem())
+	return nil
+}
+
+// nodeJSON returns the decoded JSON embedded in the node. The JSON of every
+// node is decoded once per scraping.
+func (sc *scraping) nodeJSON(node *html.Node) (any, error) {
+	if data, ok := sc.json[node]; ok {
+		return data, nil
+	}
+	text, err := ExtractJSON(node)
+	if err != nil {
+		return nil, err
+	}
+	data, err := decodeJSON(text)
+	if err != nil {
+		return nil, err
+	}
+	sc.json[node] = data
+	return data, nil
+}
+
+// decodeJSON decodes the JSON text keeping numbers as [json.Number].
+func decodeJSON(text string) (any, error) {
+	decoder := json.NewDecoder(bytes.NewBufferString(text))
+	decoder.UseNumber()
+	var data any
+	if err := decoder.Decode(&data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// jsonAt returns the value at the path of the decoded JSON.
+func jsonAt(data any, path string) (any, error) {
+	keys := strings.NewReplacer("[", ".", "]", "").Replace(path)
+	for _, key := range strings.Split(keys, ".") {
+		if key == "" {
+			continue
+		}
+		switch v := data.(type) {
+		case map[string]any:
+			val, ok := v[key]
+			if !ok {
+				return nil, JSONPathErr{Path: path, Key: key}
+			}
+			data = val
+		case []any:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, JSONPathErr{Path: path, Key: key}
+			}
+			data = v[i]
+		default:
+			return nil, JSONPathErr{Path: path, Key: key}
+		}
+	}
+	return data, nil
+}
@@ -0,0 +1,56 @@
+package scrape_test
+
+import (
+	"testing"
+
+	. "github.com/branow/htmlscraper/scrape"
+	"github.com/branow/tabtest/tab"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractJSON(t *testing.T) {
+	args := []tab.Args{
+		{
+			"@application json",
+			`<script type="application/json"> {"a": [1, 2]} </script>`,
+			`{"a": [1, 2]}`,
+			nil,
+		},
+		{
+			"@assignment",
+			`<script>window.__STATE__ = {"user": {"name": "a=b"}}; init();</script>`,
+			`{"user": {"name": "a=b"}}`,
+			nil,
+		},
+		{
+			"@bracket assignment",
+			`<script>window["__DATA__"] = [{"id": 1}];</script>`,
+			`[{"id": 1}]`,
+			nil,
+		},
+		{
+			"@object literal",
+			`<script>var state = {id: 7, name: 'Tom\'s "shop"', tags: ['a', 'b',], // comment
+				extra: undefined, /* note */ ok: true,};</script>`,
+			`{"id": 7, "name": "Tom's \"shop\"", "tags": ["a", "b"], "extra": null, "ok": true}`,
+			nil,
+		},
+		{
+			"@no json",
+			`<script>console.log("hi")</script>`,
+			"",
+			JSONNotFoundErr{},
+		},
+	}
+	test := func(t *testing.T, data string, exp string, eErr error) {
+		node := getDoc(data).Find("script").Nodes[0]
+		act, err := ExtractJSON(node)
+		assert.Equal(t, eErr, err)
+		if exp == "" {
+			assert.Empty(t, act)
+		} else {
+			assert.JSONEq(t, exp, act)
+		}
+	}
+	tab.RunWithArgs(t, args, test)
+}
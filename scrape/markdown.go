@@ -0,0 +1,322 @@
+package scrape
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// ToMarkdown converts the content of the node into Markdown. It supports
+// headings, paragraphs, lists, block quotes, code, emphasis, links, images,
+// and tables (as GitHub Flavored Markdown tables). The URLs of links and
+// images are resolved against the base URL if it is not nil. The content
+// of script, style, template, and noscript elements is skipped.
+func ToMarkdown(node *html.Node, base *url.URL) string {
+	c := markdownConverter{base: base}
+	return strings.Join(c.blocks(node), "\n\n")
+}
+
+// markdownConverter converts HTML nodes into Markdown.
+type markdownConverter struct {
+	base *url.URL
+}
+
+// blocks returns the Markdown blocks of the children of the node. The
+// consecutive inline children are joined into one block.
+func (c markdownConverter) blocks(node *html.Node) []string {
+	blocks := []string{}
+	inline := strings.Builder{}
+	flush := func() {
+		if text := cleanInline(inline.String()); text != "" {
+			blocks = append(blocks, text)
+		}
+		inline.Reset()
+	}
+
+	for n := node.FirstChild; n != nil; n = n.NextSibling {
+		if !isBlock(n) {
+			inline.WriteString(c.inline(n))
+			continue
+		}
+		flush()
+		if block := c.block(n); block != "" {
+			blocks = append(blocks, block)
+		}
+	}
+	flush()
+	return blocks
+}
+
+// block returns the Markdown of a block element.
+func (c markdownConverter) block(node *html.Node) string {
+	switch node.Data {
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		text := cleanInline(c.inlineChildren(node))
+		if text == "" {
+			return ""
+		}
+		level, _ := strconv.Atoi(node.Data[1:])
+		return strings.Repeat("#", level) + " " + strings.ReplaceAll(text, "\\\n", " ")
+	case "ul", "ol":
+		return c.list(node)
+	case "blockquote":
+		return prefixLines(strings.Join(c.blocks(node), "\n\n"), "> ", ">")
+	case "pre":
+		return c.codeBlock(node)
+	case "table":
+		return c.table(node)
+	case "hr":
+		return "---"
+	default:
+		return strings.Join(c.blocks(node), "\n\n")
+	}
+}
+
+// inline returns the Markdown of an inline node.
+func (c markdownConverter) inline(node *html.Node) string {
+	switch node.Type {
+	case html.TextNode:
+		return escapeMarkdown(collapseSpaces(node.Data))
+	case html.ElementNode:
+	default:
+		return ""
+	}
+
+	switch node.Data {
+	case "br":
+		return "\\\n"
+	case "strong", "b":
+		return wrapInline(c.inlineChildren(node), "**")
+	case "em", "i":
+		return wrapInline(c.inlineChildren(node), "*")
+	case "s", "del", "strike":
+		return wrapInline(c.inlineChildren(node), "~~")
+	case "code", "kbd", "samp":
+		return codeSpan(ExtractDeepText(node))
+	case "a":
+		text := cleanInline(c.inlineChildren(node))
+		href, err := ExtractAttribute(node, "href")
+		if err != nil {
+			return text
+		}
+		href = resolveURL(c.base, href)
+		if text == "" {
+			text = escapeMarkdown(href)
+		}
+		return fmt.Sprintf("[%s](%s)", text, markdownURL(href))
+	case "img":
+		src, err := ExtractAttribute(node, "src")
+		if err != nil {
+			return ""
+		}
+		alt, _ := ExtractAttribute(node, "alt")
+		return fmt.Sprintf("![%s](%s)", escapeMarkdown(collapseSpaces(alt)), markdownURL(resolveURL(c.base, src)))
+	default:
+		if slices.Contains(skippedElements, node.Data) {
+			return ""
+		}
+		return c.inlineChildren(node)
+	}
+}
+
+// inlineChildren returns the Markdown of the children of the node as
+// inline content, even if some of them are blocks.
+func (c markdownConverter) inlineChildren(node *html.Node) string {
+	text := strings.Builder{}
+	for n := node.FirstChild; n != nil; n = n.NextSibling {
+		if isBlock(n) {
+			text.WriteString(" ")
+			text.WriteString(c.inlineChildren(n))
+			text.WriteString(" ")
+			continue
+		}
+		text.WriteString(c.inline(n))
+	}
+	return text.String()
+}
+
+// list returns the Markdown of an ordered or unordered list.
+func (c markdownConverter) list(node *html.Node) string {
+	items := []string{}
+	number := 1
+	if start, err := ExtractAttribute(node, "start"); err == nil {
+		if n, err := strconv.Atoi(start); err == nil {
+			number = n
+		}
+	}
+	for n := node.FirstChild; n != nil; n = n.NextSibling {
+		if n.Type != html.ElementNode || n.Data != "li" {
+			continue
+		}
+		marker := "- "
+		if node.Data == "ol" {
+			marker = strconv.Itoa(number) + ". "
+			number++
+		}
+		content := strings.Join(c.blocks(n), "\n\n")
+		indent := strings.Repeat(" ", len(marker))
+		items = append(items, marker+strings.TrimPrefix(prefixLines(content, indent, ""), indent))
+	}
+	return strings.Join(items, "\n")
+}
+
+// codeBlock returns the fenced code block of a pre element. The language
+// is taken from the "language-*" class of the inner code element.
+func (c markdownConverter) codeBlock(node *html.Node) string {
+	code := strings.TrimSuffix(ExtractDeepText(node), "\n")
+	lang := ""
+	for n := node.FirstChild; n != nil; n = n.NextSibling {
+		if n.Type != html.ElementNode || n.Data != "code" {
+			continue
+		}
+		class, _ := ExtractAttribute(n, "class")
+		for _, cl := range strings.Fields(class) {
+			if strings.HasPrefix(cl, "language-") {
+				lang = strings.TrimPrefix(cl, "language-")
+			}
+		}
+	}
+	fence := "```"
+	for strings.Contains(code, fence) {
+		fence += "`"
+	}
+	return fence + lang + "\n" + code + "\n" + fence
+}
+
+// table returns the GitHub Flavored Markdown table. The first row is
+// considered as the header.
+func (c markdownConverter) table(node *html.Node) string {
+	rows := [][]string{}
+	var collect func(n *html.Node)
+	collect = func(n *html.Node) {
+		for n := n.FirstChild; n != nil; n = n.NextSibling {
+			if n.Type != html.ElementNode {
+				continue
+			}
+			switch n.Data {
+			case "thead", "tbody", "tfoot":
+				collect(n)
+			case "tr":
+				row := []string{}
+				for cell := n.FirstChild; cell != nil; cell = cell.NextSibling {
+					if cell.Type == html.ElementNode && (cell.Data == "td" || cell.Data == "th") {
+						text := cleanInline(c.inlineChildren(cell))
+						text = strings.ReplaceAll(text, "\\\n", " ")
+						row = append(row, strings.ReplaceAll(text, "|", "\\|"))
+					}
+				}
+				rows = append(rows, row)
+			}
+		}
+	}
+	collect(node)
+	if len(rows) == 0 {
+		return ""
+	}
+
+	width := 0
+	for _, row := range rows {
+		width = max(width, len(row))
+	}
+	lines := []string{}
+	for i, row := range rows {
+		for len(row) < width {
+			row = append(row, "")
+		}
+		lines = append(lines, "| "+strings.Join(row, " | ")+" |")
+		if i == 0 {
+			lines = append(lines, "|"+strings.Repeat(" --- |", width))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// isBlock reports whether the node is a block element.
+func isBlock(node *html.Node) bool {
+	return node.Type == html.ElementNode && slices.Contains(blockElements, node.Data)
+}
+
+var spaces = regexp.MustCompile(`[ \t\n\r\f]+`)
+
+// collapseSpaces replaces every sequence of whitespace with one space.
+func collapseSpaces(text string) string {
+	return spaces.ReplaceAllString(strings.ReplaceAll(text, "\u00a0", " "), " ")
+}
+
+var (
+	multiSpaces = regexp.MustCompile(` +`)
+	lineSpaces  = regexp.MustCompile(` *(\\\n) *`)
+)
+
+// cleanInline collapses the spaces of the joined inline content and trims
+// them around hard line breaks.
+func cleanInline(text string) string {
+	text = multiSpaces.ReplaceAllString(text, " ")
+	text = lineSpaces.ReplaceAllString(text, "$1")
+	for {
+		trimmed := strings.Trim(text, " ")
+		trimmed = strings.TrimPrefix(trimmed, "\\\n")
+		trimmed = strings.TrimSuffix(trimmed, "\\\n")
+		if trimmed == text {
+			return text
+		}
+		text = trimmed
+	}
+}
+
+var markdownEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "_", `\_`,
+	"`", "\\`", "[", `\[`, "]", `\]`)
+
+// escapeMarkdown escapes the characters of the text that are Markdown syntax.
+func escapeMarkdown(text string) string {
+	return markdownEscaper.Replace(text)
+}
+
+// markdownURL escapes the characters of the URL that end a Markdown link.
+func markdownURL(u string) string {
+	return strings.NewReplacer(" ", "%20", "(", "%28", ")", "%29").Replace(u)
+}
+
+// wrapInline wraps the trimmed text with the marker keeping the spaces
+// around it outside.
+func wrapInline(text, marker string) string {
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" {
+		return text
+	}
+	start := text[:strings.Index(text, trimmed)]
+	end := text[len(start)+len(trimmed):]
+	return start + marker + trimmed + marker + end
+}
+
+// codeSpan returns the inline code with enough backticks around it.
+func codeSpan(code string) string {
+	code = collapseSpaces(code)
+	fence := "`"
+	for strings.Contains(code, fence) {
+		fence += "`"
+	}
+	if strings.HasPrefix(code, "`") || strings.HasSuffix(code, "`") {
+		code = " " + code + " "
+	}
+	return fence + code + fence
+}
+
+// prefixLines adds the prefix to every non-empty line of the text and
+// replaces empty lines with the empty prefix.
+func prefixLines(text, prefix, empty string) string {
+	lines := strings.Split(text, "\n")
+	for i, l := range lines {
+		if l == "" {
+			lines[i] = empty
+		} else {
+			lines[i] = prefix + l
+		}
+	}
+	return strings.Join(lines, "\n")
+}
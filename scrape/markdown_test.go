@@ -0,0 +1,53 @@
+package scrape_test
+
+import (
+	"net/url"
+	"testing"
+
+	. "github.com/branow/htmlscraper/scrape"
+	"github.com/branow/tabtest/tab"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToMarkdown(t *testing.T) {
+	base, _ := url.Parse("https://shop.com/catalog/")
+	args := []tab.Args{
+		{
+			"@headings and emphasis",
+			`<h2>Product  1</h2><p>Great <b>product</b> for <em>your</em> needs.<br>Use <code>go get</code>.</p>`,
+			base,
+			"## Product 1\n\nGreat **product** for *your* needs.\\\nUse `go get`.",
+		},
+		{
+			"@links and images",
+			`<p><a href="/p/1">First</a> and <a href="../p/2?a=b">second_one</a></p><img src="img.png" alt="Photo">`,
+			base,
+			"[First](https://shop.com/p/1) and [second\\_one](https://shop.com/p/2?a=b)\n\n![Photo](https://shop.com/catalog/img.png)",
+		},
+		{
+			"@lists",
+			`<ul><li>one</li><li>two<ol start="3"><li>three</li><li>four</li></ol></li></ul>`,
+			nil,
+			"- one\n- two\n\n  3. three\n  4. four",
+		},
+		{
+			"@code and quote",
+			`<pre><code class="language-go">fmt.Println("hi")
+</code></pre><blockquote><p>Quote</p><p>More</p></blockquote><script>x()</script>`,
+			nil,
+			"```go\nfmt.Println(\"hi\")\n```\n\n> Quote\n>\n> More",
+		},
+		{
+			"@table",
+			`<table><tr><th>Name</th><th>Price</th></tr><tr><td>A|B</td><td>$1</td></tr><tr><td>C</td></tr></table>`,
+			nil,
+			"| Name | Price |\n| --- | --- |\n| A\\|B | $1 |\n| C |  |",
+		},
+	}
+	test := func(t *testing.T, data string, base *url.URL, exp string) {
+		doc := getDoc(`<div class="x">` + data + `</div>`)
+		act := ToMarkdown(doc.Find(".x").Nodes[0], base)
+		assert.Equal(t, exp, act)
+	}
+	tab.RunWithArgs(t, args, test)
+}
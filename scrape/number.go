@@ -0,0 +1,316 @@
+package scrape
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Money is an amount of money with its currency. It can be used as a type
+// of scraped values, which are parsed with [ParseMoney] using the locale
+// of the [NumberTag].
+type Money struct {
+	Amount   float64
+	Currency string // ISO 4217 code ("USD", "EUR"), empty if not detected
+}
+
+// numberFormat describes the separators of numbers in a locale.
+type numberFormat struct {
+	decimal rune
+	groups  string
+}
+
+var (
+	pointFormat  = numberFormat{decimal: '.', groups: ","}
+	commaFormat  = numberFormat{decimal: ',', groups: "."}
+	spacedFormat = numberFormat{decimal: ',', groups: ""}
+	swissFormat  = numberFormat{decimal: '.', groups: "'’"}
+)
+
+// localeFormats matches the languages and the locales of the form
+// "language-region" to the separators of their numbers. Spaces are group
+// separators in all the locales.
+var localeFormats = map[string]numberFormat{
+	"en": pointFormat, "hi": pointFormat, "ja": pointFormat, "zh": pointFormat,
+	"ko": pointFormat, "th": pointFormat, "he": pointFormat, "ms": pointFormat,
+	"es-mx": pointFormat, "es-us": pointFormat, "en-za": spacedFormat,
+
+	"de": commaFormat, "es": commaFormat, "it": commaFormat, "nl": commaFormat,
+	"pt": commaFormat, "id": commaFormat, "tr": commaFormat, "da": commaFormat,
+	"el": commaFormat, "ro": commaFormat, "hr": commaFormat, "sl": commaFormat,
+	"sr": commaFormat, "vi": commaFormat,
+
+	"fr": spacedFormat, "ru": spacedFormat, "uk": spacedFormat, "pl": spacedFormat,
+	"cs": spacedFormat, "sk": spacedFormat, "sv": spacedFormat, "fi": spacedFormat,
+	"nb": spacedFormat, "no": spacedFormat, "hu": spacedFormat, "bg": spacedFormat,
+	"lt": spacedFormat, "lv": spacedFormat, "et": spacedFormat, "be": spacedFormat,
+	"kk": spacedFormat,
+
+	"de-ch": swissFormat, "it-ch": swissFormat, "de-li": swissFormat,
+}
+
+// currencySymbols matches the currency symbols to their ISO 4217 codes.
+// Symbols shared by several currencies are matched to the codes of the
+// regions in regionCurrencies.
+var currencySymbols = map[string]string{
+	"$": "USD", "US$": "USD", "C$": "CAD", "CA$": "CAD", "A$": "AUD", "AU$": "AUD",
+	"NZ$": "NZD", "HK$": "HKD", "S$": "SGD", "MX$": "MXN", "R$": "BRL",
+	"€": "EUR", "£": "GBP", "¥": "JPY", "₹": "INR", "Rs.": "INR", "Rs": "INR",
+	"₽": "RUB", "₴": "UAH", "zł": "PLN", "₩": "KRW", "₺": "TRY", "₪": "ILS",
+	"₫": "VND", "₱": "PHP", "฿": "THB", "Kč": "CZK", "Ft": "HUF", "Fr.": "CHF",
+	"kr": "SEK", "kr.": "DKK", "lei": "RON",
+}
+
+// regionCurrencies matches the currency symbols shared by several
+// currencies to the codes used in the regions of locales.
+var regionCurrencies = map[string]map[string]string{
+	"$": {"ca": "CAD", "au": "AUD", "nz": "NZD", "hk": "HKD", "sg": "SGD",
+		"mx": "MXN", "ar": "ARS", "cl": "CLP", "co": "COP"},
+	"¥":  {"cn": "CNY"},
+	"kr": {"no": "NOK", "dk": "DKK", "is": "ISK"},
+}
+
+// currencyCodeRegexp matches ISO 4217 codes.
+var currencyCodeRegexp = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// ParseNumber parses a number written with the separators of the given
+// locale ("en-US", "de-DE", "en-IN"). The text around the number, such as
+// a currency symbol ("1.299,99 €"), is ignored, and only the first number
+// of the text is parsed ("19.99 (2 items)"). If the locale is empty, the
+// separators are detected: if there are both points and commas, the last
+// of them is the decimal separator; a single separator followed by three
+// digits is a group separator. Spaces and apostrophes are group separators
+// in all the locales.
+func ParseNumber(s string, locale string) (float64, error) {
+	number, err := normalizeNumber(s, locale)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseFloat(number, 64)
+}
+
+// ParseMoney parses an amount of money written with the separators of the
+// given locale (see [ParseNumber]) and detects its currency by the symbol
+// ("$1,299.99", "₹ 12,34,567") or the ISO 4217 code ("1.299,99 EUR")
+// next to the number. Symbols shared by several currencies ("$", "kr") are
+// detected by the region of the locale, so "$" is "CAD" in "en-CA". If the
+// text has several numbers, the first one next to a currency is parsed
+// ("Save 20%: $8.00"), or the first one if there is no currency.
+func ParseMoney(s string, locale string) (Money, error) {
+	tokens, err := numberTokens(s, locale)
+	if err != nil {
+		return Money{}, err
+	}
+	if len(tokens) == 0 {
+		return Money{}, NumberErr{Value: s, Locale: locale}
+	}
+
+	token, currency := tokens[0], ""
+	for i, t := range tokens {
+		prev, next := 0, len(s)
+		if i > 0 {
+			prev = tokens[i-1][1]
+		}
+		if i < len(tokens)-1 {
+			next = tokens[i+1][0]
+		}
+		currency = detectCurrency(s[prev:t[0]], s[t[1]:next], locale)
+		if currency != "" {
+			token = t
+			break
+		}
+	}
+
+	number, err := parseToken(s, token, locale)
+	if err != nil {
+		return Money{}, err
+	}
+	amount, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: amount, Currency: currency}, nil
+}
+
+// normalizeNumber returns the first number of the text written with the
+// separators of the given locale in the form accepted by strconv.
+func normalizeNumber(s string, locale string) (string, error) {
+	tokens, err := numberTokens(s, locale)
+	if err != nil {
+		return "", err
+	}
+	if len(tokens) == 0 {
+		return "", NumberErr{Value: s, Locale: locale}
+	}
+	return parseToken(s, tokens[0], locale)
+}
+
+// parseToken returns the number of the text at the bounds of the token in
+// the form accepted by strconv.
+func parseToken(s string, token [2]int, locale string) (string, error) {
+	digits := s[token[0]:token[1]]
+	format := detectFormat(digits)
+	if locale != "" {
+		format, _ = localeFormat(locale)
+	}
+
+	number := strings.Builder{}
+	if isNegative(s[:token[0]]) {
+		number.WriteByte('-')
+	}
+	decimal := false
+	for _, r := range digits {
+		switch {
+		case isDigit(r):
+			number.WriteRune(r)
+		case r == format.decimal && !decimal:
+			number.WriteByte('.')
+			decimal = true
+		case (unicode.IsSpace(r) || strings.ContainsRune(format.groups+"'’", r)) && !decimal:
+		default:
+			return "", NumberErr{Value: s, Locale: locale}
+		}
+	}
+	return number.String(), nil
+}
+
+// numberTokens returns the start and the end of every number in the text.
+// A number starts with a digit and goes on while the digits are separated
+// by the separators of the locale (points and commas if the locale is
+// empty) or apostrophes. Spaces separate only groups of three digits.
+func numberTokens(s string, locale string) ([][2]int, error) {
+	separators := ".,'’"
+	if locale != "" {
+		format, ok := localeFormat(locale)
+		if !ok {
+			return nil, LocaleErr{Locale: locale}
+		}
+		separators = string(format.decimal) + format.groups + "'’"
+	}
+
+	tokens := [][2]int{}
+	for end := 0; end < len(s); {
+		start := strings.IndexFunc(s[end:], isDigit)
+		if start < 0 {
+			break
+		}
+		start += end
+		end = numberEnd(s, start, separators)
+		tokens = append(tokens, [2]int{start, end})
+	}
+	return tokens, nil
+}
+
+// numberEnd returns the end of the number that starts at the given index.
+func numberEnd(s string, start int, separators string) int {
+	end := start
+	for end < len(s) {
+		r, size := utf8.DecodeRuneInString(s[end:])
+		rest := s[end+size:]
+		switch {
+		case isDigit(r):
+		case strings.ContainsRune(separators, r) && leadingDigits(rest) > 0:
+		case unicode.IsSpace(r) && leadingDigits(rest) == 3:
+		default:
+			return end
+		}
+		end += size
+	}
+	return end
+}
+
+// leadingDigits returns the number of digits the text starts with.
+func leadingDigits(s string) int {
+	n := strings.IndexFunc(s, func(r rune) bool { return !isDigit(r) })
+	if n < 0 {
+		return len(s)
+	}
+	return n
+}
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+// isNegative reports whether the text before a number ends with a minus
+// sign that touches the number or a currency symbol touching the number
+// ("-5", "-$5", "−€5"). A dash separated by spaces ("Only 3 left - $5") or
+// a hyphen after a word ("SKU-5") is not a minus sign.
+func isNegative(prefix string) bool {
+	prefix = strings.TrimRightFunc(prefix, func(r rune) bool { return r != '-' && r != '−' && !unicode.IsSpace(r) })
+	sign, size := utf8.DecodeLastRuneInString(prefix)
+	if sign != '-' && sign != '−' {
+		return false
+	}
+	before, _ := utf8.DecodeLastRuneInString(prefix[:len(prefix)-size])
+	return before == utf8.RuneError || unicode.IsSpace(before) || before == '('
+}
+
+// localeFormat returns the separators of the locale or of its language.
+func localeFormat(locale string) (numberFormat, bool) {
+	locale = strings.ToLower(strings.ReplaceAll(locale, "_", "-"))
+	if format, ok := localeFormats[locale]; ok {
+		return format, true
+	}
+	language, _, _ := strings.Cut(locale, "-")
+	format, ok := localeFormats[language]
+	return format, ok
+}
+
+// detectFormat detects the separators of the number.
+func detectFormat(digits string) numberFormat {
+	point, comma := strings.LastIndexByte(digits, '.'), strings.LastIndexByte(digits, ',')
+	switch {
+	case point >= 0 && comma >= 0:
+		if point > comma {
+			return pointFormat
+		}
+		return commaFormat
+	case point < 0 && comma < 0:
+		return pointFormat
+	}
+
+	separator, last := byte('.'), point
+	if comma >= 0 {
+		separator, last = ',', comma
+	}
+	integer := digits[:strings.IndexByte(digits, separator)]
+	grouped := strings.Count(digits, string(separator)) > 1 ||
+		(len(digits)-last-1 == 3 && len(integer) <= 3 && integer != "0")
+	if grouped == (separator == ',') {
+		return pointFormat
+	}
+	return commaFormat
+}
+
+// detectCurrency returns the ISO 4217 code of the currency symbol or code
+// right before or right after a number, which are the last word of the text
+// before it and the first word of the text after it, or an empty string if
+// there is none.
+func detectCurrency(before string, after string, locale string) string {
+	_, region, _ := strings.Cut(strings.ToLower(strings.ReplaceAll(locale, "_", "-")), "-")
+	for i, text := range []string{before, after} {
+		words := strings.FieldsFunc(text, func(r rune) bool {
+			return unicode.IsSpace(r) || r == '-' || r == '−' || r == '+' || r == '(' || r == ')' || r == ':'
+		})
+		if len(words) == 0 {
+			continue
+		}
+		word := words[0]
+		if i == 0 {
+			word = words[len(words)-1]
+		}
+		if code, ok := regionCurrencies[word][region]; ok {
+			return code
+		}
+		if code, ok := currencySymbols[word]; ok {
+			return code
+		}
+		if currencyCodeRegexp.MatchString(word) {
+			return word
+		}
+	}
+	return ""
+}
@@ -0,0 +1,63 @@
+package scrape_test
+
+import (
+	"testing"
+
+	. "github.com/branow/htmlscraper/scrape"
+	"github.com/branow/tabtest/tab"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseNumber(t *testing.T) {
+	args := []tab.Args{
+		{"@en", "1,299.99", "en-US", 1299.99, nil},
+		{"@de", "1.299,99", "de-DE", 1299.99, nil},
+		{"@fr with narrow spaces", "1 299,99", "fr-FR", 1299.99, nil},
+		{"@swiss", "1'299.50", "de-CH", 1299.5, nil},
+		{"@indian grouping", "12,34,567", "en-IN", 1234567.0, nil},
+		{"@language only", "3,5", "pt", 3.5, nil},
+		{"@surrounding text", "Price: -1.299,99 €", "de", -1299.99, nil},
+		{"@first number", "19.99 (2 items)", "en", 19.99, nil},
+		{"@dash before number", "In stock - 19,99 €", "", 19.99, nil},
+		{"@hyphen after word", "SKU-5", "", 5.0, nil},
+		{"@detect decimal comma", "1.299,99", "", 1299.99, nil},
+		{"@detect group comma", "1,299", "", 1299.0, nil},
+		{"@detect decimal point", "0.125", "", 0.125, nil},
+		{"@detect repeated groups", "1.234.567", "", 1234567.0, nil},
+		{"@wrong locale separators", "1.299,99", "en", 0.0, NumberErr{Value: "1.299,99", Locale: "en"}},
+		{"@no digits", "free", "", 0.0, NumberErr{Value: "free"}},
+		{"@unknown locale", "1", "xx-YY", 0.0, LocaleErr{Locale: "xx-YY"}},
+	}
+	test := func(t *testing.T, s string, locale string, exp float64, eErr error) {
+		act, err := ParseNumber(s, locale)
+		assert.Equal(t, eErr, err)
+		assert.Equal(t, exp, act)
+	}
+	tab.RunWithArgs(t, args, test)
+}
+
+func TestParseMoney(t *testing.T) {
+	args := []tab.Args{
+		{"@dollars", "$1,299.99", "", Money{Amount: 1299.99, Currency: "USD"}},
+		{"@euros after amount", "1.299,99 €", "de-DE", Money{Amount: 1299.99, Currency: "EUR"}},
+		{"@rupees", "₹ 12,34,567", "en-IN", Money{Amount: 1234567, Currency: "INR"}},
+		{"@iso code", "CHF 1'250.00", "de-CH", Money{Amount: 1250, Currency: "CHF"}},
+		{"@regional dollars", "$20", "en-CA", Money{Amount: 20, Currency: "CAD"}},
+		{"@prefixed dollars", "Now only C$ 9.99", "", Money{Amount: 9.99, Currency: "CAD"}},
+		{"@negative", "-$5.00", "", Money{Amount: -5, Currency: "USD"}},
+		{"@negative euros", "−€5", "", Money{Amount: -5, Currency: "EUR"}},
+		{"@dash before dollars", "Only 3 left - $19.99", "", Money{Amount: 19.99, Currency: "USD"}},
+		{"@dash before euros", "In stock - 19,99 €", "", Money{Amount: 19.99, Currency: "EUR"}},
+		{"@no currency", "12.50", "", Money{Amount: 12.5}},
+		{"@quantity after amount", "$19.99 (2 items)", "", Money{Amount: 19.99, Currency: "USD"}},
+		{"@percent before amount", "Save 20%: $8.00", "", Money{Amount: 8, Currency: "USD"}},
+		{"@tax rate after amount", "1.299,99 € inkl. 19% MwSt", "", Money{Amount: 1299.99, Currency: "EUR"}},
+		{"@tax rate with locale", "1.299,99 € inkl. 19% MwSt", "de-DE", Money{Amount: 1299.99, Currency: "EUR"}},
+	}
+	test := func(t *testing.T, s string, locale string, exp Money) {
+		act, err := ParseMoney(s, locale)
+		assert.NoError(t, err)
+		assert.Equal(t, exp, act)
+	}
+	tab.RunWithArgs(t, args, test)
+}
@@ -0,0 +1,135 @@
+package scrape
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/html"
+)
+
+// blockElements are the elements rendered on their own lines.
+var blockElements = []string{"address", "article", "aside", "blockquote", "body",
+	"caption", "dd", "details", "dialog", "div", "dl", "dt", "fieldset",
+	"figcaption", "figure", "footer", "form", "h1", "h2", "h3", "h4", "h5",
+	"h6", "header", "hgroup", "hr", "li", "main", "nav", "ol", "p", "pre",
+	"section", "summary", "table", "tr", "ul"}
+
+// skippedElements are the elements whose content is never rendered as text.
+var skippedElements = []string{"script", "style", "template", "noscript", "head"}
+
+// ExtractPlainText returns the text of the node laid out the way a browser
+// renders it: block elements are put on their own lines (paragraphs are
+// separated by an empty line), <br> is a line break, whitespace is collapsed,
+// and &nbsp; is a regular space. The content of script, style, template, and
+// noscript elements is skipped. If skipHidden is true, the elements hidden
+// with the hidden attribute, aria-hidden="true", or an inline style
+// (display: none, visibility: hidden) are skipped too.
+func ExtractPlainText(node *html.Node, skipHidden bool) string {
+	w := textWriter{skipHidden: skipHidden}
+	w.writeNode(node)
+	return w.b.String()
+}
+
+// textWriter writes the text of nodes collapsing whitespace between them.
+type textWriter struct {
+	b          strings.Builder
+	skipHidden bool
+
+	space  bool // a collapsed space is pending
+	breaks int  // the number of pending line breaks
+	pre    int  // the number of enclosing pre elements
+}
+
+func (w *textWriter) writeNode(node *html.Node) {
+	switch node.Type {
+	case html.TextNode:
+		w.writeText(node.Data)
+		return
+	case html.ElementNode, html.DocumentNode:
+	default:
+		return
+	}
+
+	if node.Type == html.ElementNode {
+		if slices.Contains(skippedElements, node.Data) || (w.skipHidden && isHidden(node)) {
+			return
+		}
+		switch node.Data {
+		case "br":
+			w.breaks++
+			return
+		case "td", "th":
+			if node.PrevSibling != nil {
+				w.space = true
+			}
+		case "pre":
+			w.pre++
+			defer func() { w.pre-- }()
+		}
+	}
+
+	block := node.Type == html.ElementNode && slices.Contains(blockElements, node.Data)
+	if block {
+		w.lineBreak(node)
+	}
+	for n := node.FirstChild; n != nil; n = n.NextSibling {
+		w.writeNode(n)
+	}
+	if block {
+		w.lineBreak(node)
+	}
+}
+
+// lineBreak requires the text to continue on a new line.
+func (w *textWriter) lineBreak(node *html.Node) {
+	n := 1
+	if node.Data == "p" {
+		n = 2
+	}
+	w.breaks = max(w.breaks, n)
+}
+
+func (w *textWriter) writeText(text string) {
+	for _, r := range text {
+		if w.pre == 0 && unicode.IsSpace(r) && r != '\u00a0' {
+			w.space = true
+			continue
+		}
+		if w.b.Len() == 0 {
+			w.breaks, w.space = 0, false
+		}
+		if w.breaks > 0 {
+			w.b.WriteString(strings.Repeat("\n", w.breaks))
+			w.breaks, w.space = 0, false
+		}
+		if w.space {
+			w.b.WriteByte(' ')
+			w.space = false
+		}
+		if r == '\u00a0' {
+			r = ' '
+		}
+		w.b.WriteRune(r)
+	}
+}
+
+// isHidden reports whether the element is hidden by its attributes.
+func isHidden(node *html.Node) bool {
+	for _, a := range node.Attr {
+		switch a.Key {
+		case "hidden":
+			return true
+		case "aria-hidden":
+			if strings.EqualFold(a.Val, "true") {
+				return true
+			}
+		case "style":
+			style := strings.ToLower(strings.Join(strings.Fields(a.Val), ""))
+			if strings.Contains(style, "display:none") || strings.Contains(style, "visibility:hidden") {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,58 @@
+package scrape_test
+
+import (
+	"testing"
+
+	. "github.com/branow/htmlscraper/scrape"
+	"github.com/branow/tabtest/tab"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractPlainText(t *testing.T) {
+	args := []tab.Args{
+		{
+			"@blocks",
+			`<div class="product"><h2>Product 1</h2><p>Great   product
+				for your needs.</p><p>Second</p></div>`,
+			false,
+			"Product 1\n\nGreat product for your needs.\n\nSecond",
+		},
+		{
+			"@br and nbsp",
+			`<span>a<br>b&nbsp;&nbsp;c <b>d</b></span>`,
+			false,
+			"a\nb  c d",
+		},
+		{
+			"@skipped elements",
+			`<div>text<script>var a = 1;</script><style>p {}</style><noscript>js</noscript></div>`,
+			false,
+			"text",
+		},
+		{
+			"@lists and tables",
+			`<ul><li>one</li><li>two</li></ul><table><tr><td>a</td><td>b</td></tr></table>`,
+			false,
+			"one\ntwo\na b",
+		},
+		{
+			"@pre",
+			`<p>code:</p><pre>a  b
+c</pre>`,
+			false,
+			"code:\n\na  b\nc",
+		},
+		{
+			"@hidden",
+			`<div>shown <span hidden>x</span><span style="display: none">y</span><span aria-hidden="true">z</span>end</div>`,
+			true,
+			"shown end",
+		},
+	}
+	test := func(t *testing.T, data string, skipHidden bool, exp string) {
+		doc := getDoc(`<div class="x">` + data + `</div>`)
+		act := ExtractPlainText(doc.Find(".x").Nodes[0], skipHidden)
+		assert.Equal(t, exp, act)
+	}
+	tab.RunWithArgs(t, args, test)
+}
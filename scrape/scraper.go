@@ -1,9 +1,14 @@
 package scrape
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"net/url"
 	"reflect"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/PuerkitoBio/goquery"
 	"golang.org/x/net/html"
@@ -12,10 +17,39 @@ import (
 // The tags that let you to specify where the valuable data is and how to
 // get it from the [html.Node].
 const (
-	SelectorTag  = "select"  // jQuery-like selector to find the node
-	ExtractorTag = "extract" // extract operation to get useful data from the node
+	SelectorTag  = "select"    // jQuery-like selector to find the node
+	ExtractorTag = "extract"   // extract operation to get useful data from the node
+	GroupTag     = "group"     // jQuery-like selector of nodes that split sibling nodes into groups
+	DataTag      = "data"      // name of a data-* attribute without the prefix (the same as extract:"@data-name")
+	OverflowTag  = "overflow"  // policy for more nodes than an array can hold
+	UnderflowTag = "underflow" // policy for fewer nodes than an array length
+	NumberTag    = "number"    // locale of numbers and [Money] ("en-US", "de-DE"), detected if empty
+	TimeTag      = "time"      // layouts of times separated by "|" ("2006-01-02|Jan 2, 2006")
+	TimezoneTag  = "timezone"  // IANA name of the location of times without a time zone ("Europe/Berlin")
+	SplitTag     = "split"     // separator that splits the data of a node into a slice, spaces if empty
+	JoinTag      = "join"      // separator that joins the data of all the matched nodes into a value
 )
 
+// Policies of the [OverflowTag] and [UnderflowTag].
+const (
+	TruncatePolicy = "truncate" // skip the nodes that do not fit (default overflow policy)
+	ZeroPolicy     = "zero"     // leave the rest of the elements zero (default underflow policy)
+	ErrorPolicy    = "error"    // fail if the number of nodes differs from the array length
+)
+
+// LevelSeparator separates the selectors of nesting levels of slices and
+// arrays in the [SelectorTag] ("tr;td" for a [][]string). The selector of
+// a level is relative to the nodes of the previous one.
+const LevelSeparator = ";"
+
+// SkipSelector is a special value of the [SelectorTag] that excludes
+// the field from scraping.
+const SkipSelector = "-"
+
+// HeadingSelector is a special value of the [SelectorTag] that selects the
+// node that opened the group (see [GroupTag]).
+const HeadingSelector = ":heading"
+
 type Mode uint
 
 const (
@@ -41,22 +75,101 @@ type Scraper struct {
 	// Do not use reserved extractor tag names and patterns ([TextExtractTag],
 	// [AttrExtractTag], and others), otherwise, the default implementation is executed.
 	Extractors map[*Match]Extractor
+
+	// ContextExtractors is a map that matches custom user extractors that
+	// need all the matched nodes or the context of the scraping to extract
+	// tags. They are looked up after [Scraper.Extractors].
+	ContextExtractors map[*Match]ContextExtractor
+
+	// TypedExtractors is a map that matches custom user extractors that
+	// return values of other types than string (see [TypedExtractor]).
+	// Their values are assigned to the scraped values of the same type,
+	// and to the elements of slices, arrays and pointers of that type.
+	// Otherwise [TypeErr] is returned. They are looked up before all the
+	// other extractors.
+	TypedExtractors map[*Match]ValueExtractor
+
+	// Sanitizer is used by the [SafeHTMLExtractTag] extractor. If it is nil,
+	// [DefaultSanitizer] is used.
+	Sanitizer *Sanitizer
+
+	// BaseURL is the URL of the page used to resolve relative URLs (see
+	// [URLExtractPrefix] and [MarkdownExtractTag]). If it is nil, the URL
+	// of the document ([goquery.Document.Url]) is used. In both cases the
+	// <base href> element of the document is honored.
+	BaseURL *url.URL
+
+	// Discriminators is a map that matches interface types to the
+	// discriminators that choose the concrete type of every node scraped
+	// into a value of the interface type.
+	Discriminators map[reflect.Type][]Discriminator
+
+	// IgnoreAttrCase makes the [AttrExtractTag] extractor, the [DataTag],
+	// and the attribute clauses of the [WhereTag] match names of attributes
+	// case-insensitively (see [ExtractAttributeFold]).
+	IgnoreAttrCase bool
+
+	// MaxDepth is the maximum number of nested structs, which limits
+	// the scraping of recursive types. If it is zero, [DefaultMaxDepth]
+	// is used.
+	MaxDepth int
+
+	// Clock returns the reference time that relative times ("3 hours ago",
+	// "yesterday") are parsed against (see [ParseTime]). If it is nil,
+	// [time.Now] is used.
+	Clock func() time.Time
 }
 
+// scraping is the state of a single call of [Scraper.ScrapeContext], which
+// the scrape methods share.
+type scraping struct {
+	ctx  context.Context
+	root *html.Node // root node of the document
+	base *url.URL   // URL that relative URLs are resolved against
+	now  time.Time  // reference time of relative times
+
+	// json holds the JSON decoded from the nodes (see [JSONPathTag]).
+	json map[*html.Node]any
+
+	// foreign is the copy of the document that foreign selectors are
+	// matched against.
+	foreign *foreignTree
+
+	// attribute returns the value of an attribute of a node, matching its
+	// name case-insensitively if [Scraper.IgnoreAttrCase] is set.
+	attribute func(node *html.Node, attr string) (string, error)
+}
+
+// DefaultMaxDepth is the default value of [Scraper.MaxDepth].
+const DefaultMaxDepth = 64
+
 // Scrape scrapes the given doc and writes the useful information into o.
 //
-// o must be a pointer to a string, slice, or struct, otherwise it causes an error.
-// Slices and structs both can contain pointers, strings, slices, and structs but
-// the end value must be a string.
+// o must be a pointer to a string, bool, number, slice, array, struct, or interface,
+// otherwise it causes an error. Slices, arrays, and structs can contain all of them
+// and pointers, but the end value must be a string, bool, or number. Bools and
+// numbers are parsed from the extracted strings. The concrete types of
+// interfaces are chosen by [Scraper.Discriminators].
 //
 // selector is a jQuery-like selector that specifies a path to nodes
-// (is used in [goquery.Selection.Find]). If selector is empty the doc selection
-// (it uses [goquery.Document.Selection]) is considered as default.
+// (is used in [goquery.Selection.Find]). If selector is empty the root selector
+// declared by the type of o (see [Rooted]) is used, and if there is no one
+// the doc selection (it uses [goquery.Document.Selection]) is considered
+// as default. A selector starting with a combinator ("> .replies > .comment")
+// is relative to the current nodes, which is useful for recursive types.
+// A slice or an array with a relative selector that matches nothing is
+// empty instead of causing an error.
 //
 // extract is a value that specifies how to get useful data from the node.
 // extract is required only if o is a pointer to a string or slice, in all
 // other cases you can leave it empty.
 func (scraper Scraper) Scrape(doc *goquery.Document, o any, selector string, extract string) error {
+	return scraper.ScrapeContext(context.Background(), doc, o, selector, extract)
+}
+
+// ScrapeContext works as [Scraper.Scrape] but passes the given context to
+// [ContextExtractor] functions and stops scraping when the context is done.
+func (scraper Scraper) ScrapeContext(ctx context.Context, doc *goquery.Document, o any, selector string, extract string) error {
 	err := errors.Join(ValidateNotNil(doc, "doc"), ValidateNotNil(o, "o"))
 	if err != nil {
 		return ScrapeErr{err}
@@ -68,102 +181,463 @@ func (scraper Scraper) Scrape(doc *goquery.Document, o any, selector string, ext
 	}
 	ote, ove := ot.Elem(), ov.Elem()
 
-	err = scraper.scrapeObject(doc.Selection, ote, ove, selector, extract)
+	if len(selector) == 0 {
+		selector = rootSelector(ote)
+	}
+
+	docURL := scraper.BaseURL
+	if docURL == nil {
+		docURL = doc.Url
+	}
+	sc := &scraping{ctx: ctx, json: map[*html.Node]any{}, foreign: newForeignTree(), now: time.Now()}
+	sc.attribute = ExtractAttribute
+	if scraper.IgnoreAttrCase {
+		sc.attribute = ExtractAttributeFold
+	}
+	if len(doc.Nodes) != 0 {
+		sc.root = doc.Nodes[0]
+		sc.base = baseURL(doc.Nodes[0], docURL)
+	}
+	if scraper.Clock != nil {
+		sc.now = scraper.Clock()
+	}
+
+	f := field{selector: selector, extract: extract}
+	err = scraper.scrapeObject(sc, doc.Selection, ote, ove, f)
 	if err != nil && scraper.Mode != Silent {
 		return ScrapeErr{err}
 	}
 	return nil
 }
 
-func (scraper Scraper) scrapeObject(selection *goquery.Selection, ot reflect.Type, ov reflect.Value, selector string, extract string) error {
+func (scraper Scraper) scrapeObject(sc *scraping, selection *goquery.Selection, ot reflect.Type, ov reflect.Value, f field) error {
+	if err := sc.ctx.Err(); err != nil {
+		return err
+	}
+
+	if path, ok := f.tag.Lookup(JSONPathTag); ok {
+		return scraper.scrapeJSON(sc, selection, ot, ov, f, path)
+	}
+
+	if extractor, extract, ok := scraper.typedExtractor(f.extract); ok {
+		if extractor.Type().AssignableTo(ot) {
+			return scraper.scrapeTyped(sc, selection, ot, ov, f, extractor, extract)
+		}
+		switch ot.Kind() {
+		case reflect.Slice, reflect.Array, reflect.Pointer:
+		default:
+			return ScrapingErr{Selector: f.selector, Cause: TypeErr{Exp: ot, Act: extractor.Type()}}
+		}
+	}
+
+	switch ot {
+	case reflect.TypeFor[url.URL]():
+		return scraper.scrapeURL(sc, selection, ov, f)
+	case reflect.TypeFor[[]ImageCandidate]():
+		return scraper.scrapeImages(sc, selection, ov, f)
+	case reflect.TypeFor[Money]():
+		return scraper.scrapeValue(sc, selection, ov, f)
+	case reflect.TypeFor[time.Time]():
+		return scraper.scrapeTime(sc, selection, ov, f)
+	}
+
+	if extractor, ok := structuredExtractors[f.extract]; ok {
+		return scraper.scrapeStructured(sc, selection, ot, ov, f, extractor)
+	}
+
 	switch ot.Kind() {
-	case reflect.String:
-		return scraper.scrapeString(selection, ov, selector, extract)
+	case reflect.String, reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+		return scraper.scrapeValue(sc, selection, ov, f)
 	case reflect.Slice:
-		return scraper.scrapeSlice(selection, ot, ov, selector, extract)
+		return scraper.scrapeSlice(sc, selection, ot, ov, f)
+	case reflect.Array:
+		return scraper.scrapeArray(sc, selection, ot, ov, f)
 	case reflect.Struct:
-		return scraper.scrapeStruct(selection, ot, ov, selector)
+		return scraper.scrapeStruct(sc, selection, ot, ov, f)
 	case reflect.Pointer:
-		return scraper.scrapePointer(selection, ot, ov, selector, extract)
+		return scraper.scrapePointer(sc, selection, ot, ov, f)
+	case reflect.Interface:
+		return scraper.scrapeInterface(sc, selection, ot, ov, f)
 	default:
-		kinds := []any{reflect.String, reflect.Slice, reflect.Struct, reflect.Pointer}
+		kinds := []any{reflect.String, reflect.Bool, reflect.Int, reflect.Uint, reflect.Float64,
+			reflect.Slice, reflect.Array, reflect.Struct, reflect.Pointer, reflect.Interface}
 		return KindErr{Var: "o", KindExp: kinds, KindAct: ot.Kind()}
 	}
 }
 
-func (scraper Scraper) scrapeString(selection *goquery.Selection, ov reflect.Value, selector string, extract string) error {
-	if len(selector) != 0 {
-		selection = selection.Find(selector)
+func (scraper Scraper) scrapeValue(sc *scraping, selection *goquery.Selection, ov reflect.Value, f field) error {
+	val, err := scraper.extractValue(sc, selection, ov.Type(), f)
+	if err != nil {
+		return err
+	}
+
+	err = scraper.decodeValue(sc, ov, val, f.format)
+	if err != nil {
+		return ScrapingErr{Selector: f.selector, Cause: err}
+	}
+	return nil
+}
+
+// decodeValue converts the extracted data into ov according to the format.
+// ov must be a string, bool, integer, float, [Money], or [time.Time].
+func (scraper Scraper) decodeValue(sc *scraping, ov reflect.Value, val string, fm format) error {
+	if fm.err != nil {
+		return fm.err
+	}
+	var err error
+	if fm.enum != nil {
+		val, err = mapEnum(fm.enum, val)
+		if err != nil {
+			return err
+		}
+	}
+
+	switch ov.Type() {
+	case reflect.TypeFor[Money]():
+		money, err := ParseMoney(val, fm.locale)
+		if err != nil {
+			return err
+		}
+		ov.Set(reflect.ValueOf(money))
+		return nil
+	case reflect.TypeFor[time.Time]():
+		t, err := ParseTime(val, fm.layouts, fm.location, sc.now)
+		if err != nil {
+			return err
+		}
+		ov.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	if fm.number && ov.Kind() != reflect.String && ov.Kind() != reflect.Bool {
+		val, err = normalizeNumber(val, fm.locale)
+		if err != nil {
+			return err
+		}
+	}
+	return setValue(ov, val)
+}
+
+func (scraper Scraper) scrapeURL(sc *scraping, selection *goquery.Selection, ov reflect.Value, f field) error {
+	val, err := scraper.extractValue(sc, selection, ov.Type(), f)
+	if err != nil {
+		return err
+	}
+
+	u, err := url.Parse(strings.TrimSpace(val))
+	if err != nil {
+		return ScrapingErr{Selector: f.selector, Cause: err}
+	}
+	if sc.base != nil {
+		u = sc.base.ResolveReference(u)
+	}
+
+	ov.Set(reflect.ValueOf(*u))
+	return nil
+}
+
+// scrapeTime scrapes a time from the data extracted by the extract tag,
+// or from the datetime attribute of a <time> element or the text of the
+// node if there is no extract tag.
+func (scraper Scraper) scrapeTime(sc *scraping, selection *goquery.Selection, ov reflect.Value, f field) error {
+	var val string
+	if f.extract == "" {
+		selection = f.find(sc.foreign, selection)
+		if selection.Size() == 0 {
+			return ScrapingErr{Selector: f.selector, Cause: NoNodesFoundErr{}}
+		}
+		val = timeText(selection.Nodes[0])
+	} else {
+		var err error
+		val, err = scraper.extractValue(sc, selection, ov.Type(), f)
+		if err != nil {
+			return err
+		}
 	}
 
+	err := scraper.decodeValue(sc, ov, val, f.format)
+	if err != nil {
+		return ScrapingErr{Selector: f.selector, Cause: err}
+	}
+	return nil
+}
+
+func (scraper Scraper) scrapeImages(sc *scraping, selection *goquery.Selection, ov reflect.Value, f field) error {
+	selection = f.find(sc.foreign, selection)
+
 	if selection.Size() == 0 {
-		return ScrapingErr{Selector: selector, Cause: NoNodesFoundErr{}}
+		return ScrapingErr{Selector: f.selector, Cause: NoNodesFoundErr{}}
 	}
 
-	node := selection.Nodes[0]
-	val, err := scraper.toExtract(node, extract)
+	candidates := ExtractImages(selection.Nodes[0])
+	if len(candidates) == 0 {
+		return ScrapingErr{Selector: f.selector, Cause: ImageNotFoundErr{}}
+	}
+	for i, c := range candidates {
+		candidates[i].URL = resolveURL(sc.base, c.URL)
+	}
 
+	ov.Set(reflect.ValueOf(candidates))
+	return nil
+}
+
+func (scraper Scraper) scrapeStructured(sc *scraping, selection *goquery.Selection, ot reflect.Type, ov reflect.Value, f field, extractor func(*html.Node) any) error {
+	selection = f.find(sc.foreign, selection)
+
+	if selection.Size() == 0 {
+		return ScrapingErr{Selector: f.selector, Cause: NoNodesFoundErr{}}
+	}
+
+	val := reflect.ValueOf(extractor(selection.Nodes[0]))
+	if !val.Type().AssignableTo(ot) {
+		return ScrapingErr{Selector: f.selector, Cause: TypeErr{Exp: ot, Act: val.Type()}}
+	}
+
+	ov.Set(val)
+	return nil
+}
+
+func (scraper Scraper) scrapeTyped(sc *scraping, selection *goquery.Selection, ot reflect.Type, ov reflect.Value, f field, extractor ValueExtractor, extract string) error {
+	selection = f.find(sc.foreign, selection)
+
+	if selection.Size() == 0 {
+		return ScrapingErr{Selector: f.selector, Cause: NoNodesFoundErr{}}
+	}
+
+	ec := scraper.extractContext(sc, selection, ot, f)
+	ec.Extract = extract
+	val, err := extractor.ExtractValue(ec)
 	if err != nil {
-		return ScrapingErr{Selector: selector, Cause: err}
+		return ScrapingErr{Selector: f.selector, Cause: err}
 	}
 
-	ov.SetString(val)
+	ov.Set(val)
 	return nil
 }
 
-func (scraper Scraper) scrapeSlice(selection *goquery.Selection, ot reflect.Type, ov reflect.Value, selector string, extract string) error {
-	if len(selector) != 0 {
-		selection = selection.Find(selector)
+// typedExtractor returns the typed extractor matched by the extract tag
+// and the tag processed by its [Match].
+func (scraper Scraper) typedExtractor(extract string) (ValueExtractor, string, bool) {
+	for match, extractor := range scraper.TypedExtractors {
+		if extract, ok := (*match)(extract); ok {
+			return extractor, extract, true
+		}
+	}
+	return nil, "", false
+}
+
+// extractContext returns the context of extraction from the selection for
+// a value of the given type.
+func (scraper Scraper) extractContext(sc *scraping, selection *goquery.Selection, ot reflect.Type, f field) ExtractContext {
+	return ExtractContext{
+		Context:   sc.ctx,
+		Selection: selection,
+		Root:      sc.root,
+		Field:     f.sf,
+		Type:      ot,
+		BaseURL:   sc.base,
+	}
+}
+
+// extractValue returns the data extracted from the nodes matched by the
+// field selector for a value of the given type, or the metadata of the
+// matched nodes.
+func (scraper Scraper) extractValue(sc *scraping, selection *goquery.Selection, ot reflect.Type, f field) (string, error) {
+	selection = f.find(sc.foreign, selection)
+
+	switch f.extract {
+	case ExistsExtractTag:
+		return strconv.FormatBool(selection.Size() != 0), nil
+	case CountExtractTag:
+		return strconv.Itoa(selection.Size()), nil
+	case IndexExtractTag:
+		return strconv.Itoa(f.position), nil
 	}
 
 	if selection.Size() == 0 {
-		return ScrapingErr{Selector: selector, Cause: NoNodesFoundErr{}}
+		return "", ScrapingErr{Selector: f.selector, Cause: NoNodesFoundErr{}}
 	}
 
+	if sep, ok := f.tag.Lookup(JoinTag); ok {
+		return scraper.joinValues(sc, selection, ot, f, sep)
+	}
+
+	ec := scraper.extractContext(sc, selection, ot, f)
+	val, err := scraper.toExtract(sc, ec, f.extract)
+
+	if err != nil {
+		return "", ScrapingErr{Selector: f.selector, Cause: err}
+	}
+
+	return val, nil
+}
+
+// joinValues returns the data extracted from every node of the selection
+// joined with the separator (see [JoinTag]). The data is trimmed, and
+// empty data is dropped.
+func (scraper Scraper) joinValues(sc *scraping, selection *goquery.Selection, ot reflect.Type, f field, sep string) (string, error) {
+	vals := []string{}
+	for i := range selection.Nodes {
+		ec := scraper.extractContext(sc, selection.Eq(i), ot, f)
+		val, err := scraper.toExtract(sc, ec, f.extract)
+		if err != nil {
+			s := fmt.Sprintf("%s:n(%d)", f.selector, i)
+			return "", ScrapingErr{Selector: s, Cause: err}
+		}
+		if val = strings.TrimSpace(val); val != "" {
+			vals = append(vals, val)
+		}
+	}
+	return strings.Join(vals, sep), nil
+}
+
+func (scraper Scraper) scrapeSlice(sc *scraping, selection *goquery.Selection, ot reflect.Type, ov reflect.Value, f field) error {
+	if sep, ok := f.tag.Lookup(SplitTag); ok {
+		return scraper.scrapeSplit(sc, selection, ot, ov, f, sep)
+	}
+
+	f, fe := f.level()
+	items, err := f.items(sc, selection)
+	if err != nil {
+		return err
+	}
+
+	sv, err := scraper.scrapeItems(sc, items, ot, f, fe)
+	return scraper.setSlice(ov, sv, f, err)
+}
+
+// setSlice arranges the elements of the scraped slice (see [UniqueTag],
+// [SortTag], and [LimitTag]) and sets it to ov, unless scraping failed
+// in the [Strict] mode.
+func (scraper Scraper) setSlice(ov reflect.Value, sv reflect.Value, f field, err error) error {
+	if err != nil && scraper.Mode == Strict {
+		return err
+	}
+
+	sv, arrangeErr := arrangeSlice(sv, f.tag)
+	if arrangeErr != nil {
+		arrangeErr = ScrapingErr{Selector: f.selector, Cause: arrangeErr}
+		if scraper.Mode == Strict {
+			return arrangeErr
+		}
+		err = errors.Join(err, arrangeErr)
+	}
+
+	ov.Set(sv)
+	return err
+}
+
+func (scraper Scraper) scrapeArray(sc *scraping, selection *goquery.Selection, ot reflect.Type, ov reflect.Value, f field) error {
+	f, fe := f.level()
+	items, err := f.items(sc, selection)
+	if err != nil {
+		return err
+	}
+
+	if err := f.checkSize(ov.Len(), len(items)); err != nil {
+		return ScrapingErr{Selector: f.selector, Cause: err}
+	}
+	items = items[:min(len(items), ov.Len())]
+
+	sv, err := scraper.scrapeItems(sc, items, reflect.SliceOf(ot.Elem()), f, fe)
+	if err == nil || scraper.Mode != Strict {
+		av := reflect.New(ot).Elem()
+		reflect.Copy(av, sv)
+		ov.Set(av)
+	}
+
+	return err
+}
+
+// scrapeSplit scrapes the parts of the data extracted from the first node
+// into the elements of a new slice (see [SplitTag]).
+func (scraper Scraper) scrapeSplit(sc *scraping, selection *goquery.Selection, ot reflect.Type, ov reflect.Value, f field, sep string) error {
+	val, err := scraper.extractValue(sc, selection, ot, f)
+	if err != nil {
+		return err
+	}
+
+	parts := splitText(val, sep)
+	sv := reflect.MakeSlice(ot, 0, len(parts))
+	errs := []error{}
+	for i, part := range parts {
+		ve := reflect.New(ot.Elem()).Elem()
+		err := scraper.decodeValue(sc, ve, part, f.format)
+		if err != nil {
+			s := fmt.Sprintf("%s:n(%d)", f.selector, i)
+			errs = append(errs, ScrapingErr{Selector: s, Cause: err})
+		}
+		sv = reflect.Append(sv, ve)
+		if err != nil && scraper.Mode == Strict {
+			break
+		}
+	}
+
+	return scraper.setSlice(ov, sv, f, errors.Join(errs...))
+}
+
+// scrapeItems scrapes every item into an element of a new slice of the given type.
+func (scraper Scraper) scrapeItems(sc *scraping, items []*goquery.Selection, ot reflect.Type, f, fe field) (reflect.Value, error) {
 	ote := ot.Elem()
-	sv := reflect.MakeSlice(ot, 0, 10)
+	sv := reflect.MakeSlice(ot, 0, len(items))
 
 	errs := []error{}
-	selection.EachWithBreak(func(i int, selection *goquery.Selection) bool {
+	for i, item := range items {
 		ve := reflect.New(ote).Elem()
-		err := scraper.scrapeObject(selection, ote, ve, "", extract)
+		fe.position = i
+		err := scraper.scrapeObject(sc, item, ote, ve, fe)
 		if err != nil {
-			s := fmt.Sprintf("%s:n(%d)", selector, i)
+			s := fmt.Sprintf("%s:n(%d)", f.selector, i)
 			err := ScrapingErr{Selector: s, Cause: err}
 			errs = append(errs, err)
 		}
 		sv = reflect.Append(sv, ve)
-		return !(err != nil && scraper.Mode == Strict)
-	})
-
-	err := errors.Join(errs...)
-	if err == nil || scraper.Mode != Strict {
-		ov.Set(sv)
+		if err != nil && scraper.Mode == Strict {
+			break
+		}
 	}
 
-	return err
+	return sv, errors.Join(errs...)
 }
 
-func (scraper Scraper) scrapeStruct(selection *goquery.Selection, ot reflect.Type, ov reflect.Value, selector string) error {
-	if len(selector) != 0 {
-		selection = selection.Find(selector)
-	}
+func (scraper Scraper) scrapeStruct(sc *scraping, selection *goquery.Selection, ot reflect.Type, ov reflect.Value, f field) error {
+	selection = f.find(sc.foreign, selection)
 
 	if selection.Size() == 0 {
-		return ScrapingErr{Selector: selector, Cause: NoNodesFoundErr{}}
+		return ScrapingErr{Selector: f.selector, Cause: NoNodesFoundErr{}}
+	}
+
+	if f.depth >= scraper.maxDepth() {
+		return ScrapingErr{Selector: f.selector, Cause: DepthErr{Depth: scraper.maxDepth()}}
 	}
 
 	errs := []error{}
-	selection = selection.First()
+	grouped := f.grouped && f.selector == ""
+	if !grouped {
+		selection = selection.First()
+	}
 
-	for i := 0; i < ov.NumField(); i++ {
-		ft, fv := ot.Field(i), ov.Field(i)
-		newSelection, _ := ft.Tag.Lookup(SelectorTag)
-		newExtractor, _ := ft.Tag.Lookup(ExtractorTag)
-		err := scraper.scrapeObject(selection, ft.Type, fv, newSelection, newExtractor)
+	for _, ff := range structFields(ot) {
+		ff.grouped, ff.depth, ff.position = grouped, f.depth+1, f.position
+		fv := ff.value(ov)
+		err := scraper.scrapeObject(sc, selection, ff.sf.Type, fv, ff)
+		if err == nil {
+			err = validateField(ff, fv)
+		}
 
 		if err != nil {
-			err := ScrapingErr{Selector: selector, Cause: err}
+			err := ScrapingErr{Selector: f.selector, Cause: err}
+			if scraper.Mode == Strict {
+				return err
+			}
+			errs = append(errs, err)
+		}
+	}
+
+	for _, hook := range []func(reflect.Value) error{afterScrape, validate} {
+		if err := hook(ov); err != nil {
+			err := ScrapingErr{Selector: f.selector, Cause: err}
 			if scraper.Mode == Strict {
 				return err
 			}
@@ -174,40 +648,107 @@ func (scraper Scraper) scrapeStruct(selection *goquery.Selection, ot reflect.Typ
 	return errors.Join(errs...)
 }
 
-func (scraper Scraper) scrapePointer(selection *goquery.Selection, ot reflect.Type, ov reflect.Value, selector, extract string) error {
-	if len(selector) != 0 {
-		selection = selection.Find(selector)
-	}
+func (scraper Scraper) scrapePointer(sc *scraping, selection *goquery.Selection, ot reflect.Type, ov reflect.Value, f field) error {
+	selection = f.find(sc.foreign, selection)
 
-	if selection.Size() == 0 {
-		return ScrapingErr{Selector: selector, Cause: NoNodesFoundErr{}}
+	if selection.Size() == 0 && !isMetadataTag(f.extract) {
+		return ScrapingErr{Selector: f.selector, Cause: NoNodesFoundErr{}}
 	}
 
 	ote := ot.Elem()
 	newValue := reflect.New(ote)
-	err := scraper.scrapeObject(selection, ote, newValue.Elem(), "", extract)
+	fe := f.elem()
+	err := scraper.scrapeObject(sc, selection, ote, newValue.Elem(), fe)
 
 	if err != nil {
-		err = ScrapingErr{Selector: selector, Cause: err}
+		err = ScrapingErr{Selector: f.selector, Cause: err}
 	}
 
 	ov.Set(newValue)
 	return err
 }
 
-func (s Scraper) toExtract(node *html.Node, extract string) (string, error) {
+func (scraper Scraper) scrapeInterface(sc *scraping, selection *goquery.Selection, ot reflect.Type, ov reflect.Value, f field) error {
+	selection = f.find(sc.foreign, selection)
+
+	if selection.Size() == 0 {
+		return ScrapingErr{Selector: f.selector, Cause: NoNodesFoundErr{}}
+	}
+
+	selection = selection.First()
+	ct, err := scraper.discriminate(selection, ot)
+	if err != nil {
+		return ScrapingErr{Selector: f.selector, Cause: err}
+	}
+
+	cv := reflect.New(ct).Elem()
+	fe := f.elem()
+	fe.grouped = false
+	err = scraper.scrapeObject(sc, selection, ct, cv, fe)
+
+	if err != nil {
+		err = ScrapingErr{Selector: f.selector, Cause: err}
+		if scraper.Mode == Strict {
+			return err
+		}
+	}
+
+	ov.Set(cv)
+	return err
+}
+
+func (scraper Scraper) maxDepth() int {
+	if scraper.MaxDepth > 0 {
+		return scraper.MaxDepth
+	}
+	return DefaultMaxDepth
+}
+
+func (s Scraper) toExtract(sc *scraping, ec ExtractContext, extract string) (string, error) {
+	node := ec.Selection.Nodes[0]
+	switch {
+	case extract == SafeHTMLExtractTag && s.Sanitizer != nil:
+		return s.Sanitizer.Sanitize(node)
+	case strings.HasPrefix(extract, AttrExtractTag) && s.IgnoreAttrCase:
+		return ExtractAttributeFold(node, strings.TrimPrefix(extract, AttrExtractTag))
+	case strings.HasPrefix(extract, URLExtractPrefix):
+		val, err := s.toExtract(sc, ec, strings.TrimPrefix(extract, URLExtractPrefix))
+		if err != nil {
+			return "", err
+		}
+		return resolveURL(ec.BaseURL, strings.TrimSpace(val)), nil
+	case strings.HasPrefix(extract, TemplateExtractPrefix):
+		return s.extractTemplate(sc, ec, strings.TrimPrefix(extract, TemplateExtractPrefix))
+	}
 	defaultMap := GetExtractorMap()
 	for match, extractor := range defaultMap {
 		extract, ok := (*match)(extract)
 		if ok {
-			return extractor(node, extract)
+			ec.Extract = extract
+			return AdaptExtractor(extractor)(ec)
+		}
+	}
+	for match, extractor := range GetContextExtractorMap() {
+		extract, ok := (*match)(extract)
+		if ok {
+			ec.Extract = extract
+			return extractor(ec)
 		}
 	}
 	customMap := s.Extractors
 	for match, extractor := range customMap {
 		extract, ok := (*match)(extract)
 		if ok {
-			return extractor(node, extract)
+			ec.Extract = extract
+			return AdaptExtractor(extractor)(ec)
+		}
+	}
+	contextMap := s.ContextExtractors
+	for match, extractor := range contextMap {
+		extract, ok := (*match)(extract)
+		if ok {
+			ec.Extract = extract
+			return extractor(ec)
 		}
 	}
 	return "", ExtractTagErr{ExtractTag: extract}
@@ -2,9 +2,15 @@ package scrape_test
 
 import (
 	"bytes"
+	"context"
 	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/PuerkitoBio/goquery"
 	. "github.com/branow/htmlscraper/scrape"
@@ -14,24 +20,43 @@ import (
 )
 
 type ScrapeCfg struct {
-	CaseName   string
-	extractors map[*Match]Extractor
-	mode       Mode
-	doc        *goquery.Document
-	o          any
-	selector   string
-	extract    string
-	exp        any
-	eErr       error
+	CaseName       string
+	ctx            context.Context
+	extractors     map[*Match]Extractor
+	ctxExtractors  map[*Match]ContextExtractor
+	typedExtrs     map[*Match]ValueExtractor
+	discriminators map[reflect.Type][]Discriminator
+	mode           Mode
+	maxDepth       int
+	baseURL        *url.URL
+	ignoreAttrCase bool
+	sanitizer      *Sanitizer
+	clock          func() time.Time
+	doc            *goquery.Document
+	o              any
+	selector       string
+	extract        string
+	exp            any
+	eErr           error
 }
 
 func test(t *testing.T, c ScrapeCfg) {
-	scraper := Scraper{Mode: c.mode}
+	scraper := Scraper{Mode: c.mode, MaxDepth: c.maxDepth, BaseURL: c.baseURL, Discriminators: c.discriminators}
 	if c.extractors != nil {
 		scraper.Extractors = c.extractors
 	}
+	scraper.ContextExtractors = c.ctxExtractors
+	scraper.TypedExtractors = c.typedExtrs
+	scraper.Clock = c.clock
+	scraper.IgnoreAttrCase = c.ignoreAttrCase
+	scraper.Sanitizer = c.sanitizer
 
-	aErr := scraper.Scrape(c.doc, c.o, c.selector, c.extract)
+	var aErr error
+	if c.ctx != nil {
+		aErr = scraper.ScrapeContext(c.ctx, c.doc, c.o, c.selector, c.extract)
+	} else {
+		aErr = scraper.Scrape(c.doc, c.o, c.selector, c.extract)
+	}
 
 	if c.eErr == nil {
 		assert.NoError(t, aErr)
@@ -165,6 +190,1252 @@ func TestScraper_Scrape_ScrapeSlice(t *testing.T) {
 			selector: "#top > div",
 			exp:      &[]S{{"con"}, {"noc"}},
 		},
+		{
+			CaseName: "level separator in quotes",
+			doc:      getDoc(`<p style="a;b">x</p><p style="c">y</p>`),
+			o:        &[]string{},
+			selector: "p[style*='a;b']",
+			extract:  "text",
+			exp:      &[]string{"x"},
+		},
+		{
+			CaseName: "levels with separator in quotes",
+			doc:      getDoc(`<ul title="a;b"><li>x</li></ul><ul><li>y</li></ul>`),
+			o:        &[][]string{},
+			selector: "ul[title='a;b']; li",
+			extract:  "text",
+			exp:      &[][]string{{"x"}},
+		},
+	}
+	tab.RunWithCfgs(t, cfgs, test)
+}
+
+func TestScraper_Scrape_ScrapeGroup(t *testing.T) {
+	htmldata := `<article><p>Intro</p><h2>First</h2><p>a</p><p>b</p><h2>Second</h2><ul><li>c</li></ul></article>`
+	type Section struct {
+		Title string   `select:":heading" extract:"text"`
+		Texts []string `select:"p, li" extract:"text"`
+	}
+	type Article struct {
+		Sections []Section `select:"article" group:"h2"`
+	}
+	type Headings struct {
+		Titles []*string `select:"article" group:"h2" extract:"text"`
+	}
+	first, second := "First", "Second"
+	cfgs := []ScrapeCfg{
+		{
+			CaseName: "sections",
+			mode:     Tolerant,
+			doc:      getDoc(htmldata),
+			o:        &Article{},
+			exp: &Article{Sections: []Section{
+				{Title: "First", Texts: []string{"a", "b"}},
+				{Title: "Second", Texts: []string{"c"}},
+			}},
+		},
+		{
+			CaseName: "headings",
+			doc:      getDoc(htmldata),
+			o:        &Headings{},
+			exp:      &Headings{Titles: []*string{&first, &second}},
+		},
+		{
+			CaseName: "several containers",
+			doc:      getDoc(htmldata + `<article><h2>Third</h2><p>d</p></article>`),
+			o:        &Article{},
+			exp: &Article{Sections: []Section{
+				{Title: "First", Texts: []string{"a", "b"}},
+				{Title: "Second", Texts: []string{"c"}},
+				{Title: "Third", Texts: []string{"d"}},
+			}},
+		},
+		{
+			CaseName: "no groups",
+			doc:      getDoc(`<article><p>Intro</p></article>`),
+			o:        &Article{},
+			exp:      &Article{},
+			eErr:     ScrapeErr{ScrapingErr{Selector: "article", Cause: NoNodesFoundErr{}}},
+		},
+	}
+	tab.RunWithCfgs(t, cfgs, test)
+}
+
+func TestScraper_Scrape_ScrapeRecursive(t *testing.T) {
+	htmldata := `<div class="comment"><p>A</p><div class="replies">` +
+		`<div class="comment"><p>B</p></div>` +
+		`<div class="comment"><p>C</p><div class="replies"><div class="comment"><p>D</p></div></div></div>` +
+		`</div></div>`
+	type Comment struct {
+		Text    string    `select:"> p" extract:"text"`
+		Replies []Comment `select:"> .replies > .comment"`
+	}
+	type Loop struct {
+		Text string `select:"> p" extract:"text"`
+		Self *Loop
+	}
+	type Thread struct {
+		Texts []string `select:"> p, > .replies > .comment > p" extract:"text"`
+	}
+	cfgs := []ScrapeCfg{
+		{
+			CaseName: "comment tree",
+			doc:      getDoc(htmldata),
+			o:        &Comment{},
+			selector: ".comment",
+			exp: &Comment{Text: "A", Replies: []Comment{
+				{Text: "B", Replies: []Comment{}},
+				{Text: "C", Replies: []Comment{{Text: "D", Replies: []Comment{}}}},
+			}},
+		},
+		{
+			CaseName: "max depth",
+			maxDepth: 2,
+			doc:      getDoc(htmldata),
+			o:        &Loop{},
+			selector: ".comment",
+			exp:      &Loop{Text: "A", Self: &Loop{Text: "A", Self: &Loop{}}},
+			eErr:     ScrapeErr{ScrapingErr{Selector: ".comment", Cause: DepthErr{Depth: 2}}},
+		},
+		{
+			CaseName: "group of relative selectors",
+			doc:      getDoc(htmldata),
+			o:        &Thread{},
+			selector: ".comment",
+			exp:      &Thread{Texts: []string{"A", "B", "C"}},
+		},
+	}
+	tab.RunWithCfgs(t, cfgs, test)
+}
+
+type Author struct {
+	Author string `select:".author" extract:"text"`
+}
+
+type Timestamps struct {
+	Created string `select:".created" extract:"text"`
+}
+
+type Post struct {
+	Timestamps
+	*Author
+	Title string `select:"h2" extract:"text"`
+}
+
+func (Post) RootSelector() string {
+	return ".post"
+}
+
+func TestScraper_Scrape_ScrapeEmbedded(t *testing.T) {
+	htmldata := `<div class="post"><h2>Go</h2><span class="author">Bob</span><span class="created">today</span></div>`
+	type Card struct {
+		_     struct{} `select:".post"`
+		Title string   `select:"h2" extract:"text"`
+	}
+	type Shadow struct {
+		Timestamps
+		Created string `select:"h2" extract:"text"`
+	}
+	cfgs := []ScrapeCfg{
+		{
+			CaseName: "promoted fields",
+			doc:      getDoc(htmldata),
+			o:        &Post{},
+			exp:      &Post{Timestamps: Timestamps{Created: "today"}, Author: &Author{Author: "Bob"}, Title: "Go"},
+		},
+		{
+			CaseName: "blank field root selector",
+			doc:      getDoc(`<h2>Header</h2>` + htmldata),
+			o:        &[]Card{},
+			exp:      &[]Card{{Title: "Go"}},
+		},
+		{
+			CaseName: "shadowed field",
+			doc:      getDoc(htmldata),
+			o:        &Shadow{},
+			exp:      &Shadow{Created: "Go"},
+		},
+	}
+	tab.RunWithCfgs(t, cfgs, test)
+}
+
+type Item struct {
+	ID    string `select:"-"`
+	Name  string `select:"h2" extract:"text"`
+	price string
+}
+
+func (i *Item) AfterScrape() error {
+	if i.Name == "" {
+		return errors.New("empty name")
+	}
+	i.ID = strings.ToLower(i.Name)
+	return nil
+}
+
+func TestScraper_Scrape_ScrapeSkipped(t *testing.T) {
+	cfgs := []ScrapeCfg{
+		{
+			CaseName: "skipped and computed fields",
+			doc:      getDoc(`<div><h2>Phone</h2><p class="price">10</p></div>`),
+			o:        &Item{},
+			selector: "div",
+			exp:      &Item{ID: "phone", Name: "Phone"},
+		},
+		{
+			CaseName: "hook error",
+			mode:     Tolerant,
+			doc:      getDoc(`<div><h2></h2></div>`),
+			o:        &Item{},
+			selector: "div",
+			exp:      &Item{},
+			eErr:     ScrapeErr{ScrapingErr{Selector: "div", Cause: errors.New("empty name")}},
+		},
+	}
+	tab.RunWithCfgs(t, cfgs, test)
+}
+
+type Offer struct {
+	Name   string   `select:"h2" extract:"text" validate:"required,minlen=3" pattern:"^Product"`
+	Status string   `select:".status" extract:"text" validate:"oneof=new|used"`
+	Tags   []string `select:".tag" extract:"text" validate:"maxlen=2"`
+}
+
+func (o *Offer) Validate() error {
+	if o.Status == "used" && len(o.Tags) == 0 {
+		return errors.New("used offer without tags")
+	}
+	return nil
+}
+
+func TestScraper_Scrape_ScrapeValidate(t *testing.T) {
+	type Stock struct {
+		Count int `select:".count" extract:"text" validate:"required"`
+	}
+	cfgs := []ScrapeCfg{
+		{
+			CaseName: "valid",
+			doc:      getDoc(`<div><h2>Product 1</h2><p class="status">new</p><i class="tag">a</i></div>`),
+			o:        &Offer{},
+			selector: "div",
+			exp:      &Offer{Name: "Product 1", Status: "new", Tags: []string{"a"}},
+		},
+		{
+			CaseName: "invalid fields",
+			mode:     Tolerant,
+			doc:      getDoc(`<div><h2>Add to cart</h2><p class="status">old</p><i class="tag">a</i><i class="tag">b</i><i class="tag">c</i></div>`),
+			o:        &Offer{},
+			selector: "div",
+			exp:      &Offer{Name: "Add to cart", Status: "old", Tags: []string{"a", "b", "c"}},
+			eErr: ScrapeErr{errors.Join(
+				ScrapingErr{Selector: "div", Cause: ValidationErr{Field: "Name", Rule: "pattern=^Product", Value: "Add to cart"}},
+				ScrapingErr{Selector: "div", Cause: ValidationErr{Field: "Status", Rule: "oneof=new|used", Value: "old"}},
+				ScrapingErr{Selector: "div", Cause: ValidationErr{Field: "Tags", Rule: "maxlen=2", Value: []string{"a", "b", "c"}}},
+			)},
+		},
+		{
+			CaseName: "validate method",
+			doc:      getDoc(`<div><h2>Product 2</h2><p class="status">used</p></div>`),
+			mode:     Silent,
+			o:        &Offer{},
+			selector: "div",
+			exp:      &Offer{Name: "Product 2", Status: "used"},
+		},
+		{
+			CaseName: "validate method error",
+			doc:      getDoc(`<div><h2>Product 2</h2><p class="status">used</p></div>`),
+			mode:     Tolerant,
+			o:        &Offer{},
+			selector: "div",
+			exp:      &Offer{Name: "Product 2", Status: "used"},
+			eErr: ScrapeErr{errors.Join(
+				ScrapingErr{Selector: "div", Cause: ScrapingErr{Selector: ".tag", Cause: NoNodesFoundErr{}}},
+				ScrapingErr{Selector: "div", Cause: errors.New("used offer without tags")},
+			)},
+		},
+		{
+			CaseName: "invalid number",
+			doc:      getDoc(`<div><p class="count">0</p></div>`),
+			o:        &Stock{},
+			selector: "div",
+			exp:      &Stock{},
+			eErr:     errors.New(`scrape: div field Count: value 0 violates rule "required"`),
+		},
+	}
+	tab.RunWithCfgs(t, cfgs, test)
+}
+
+type Card interface {
+	Kind() string
+}
+
+type VideoCard struct {
+	Video string `select:"video" extract:"@src"`
+}
+
+func (VideoCard) Kind() string { return "video" }
+
+type ArticleCard struct {
+	Title string `select:"h3" extract:"text"`
+}
+
+func (*ArticleCard) Kind() string { return "article" }
+
+func TestScraper_Scrape_ScrapeInterface(t *testing.T) {
+	htmldata := `<div class="feed">` +
+		`<div class="card video"><video src="a.mp4"></video></div>` +
+		`<div class="card" data-type="article"><h3>News</h3></div>` +
+		`<div class="card ad">Buy</div>` +
+		`</div>`
+	discriminators := map[reflect.Type][]Discriminator{
+		reflect.TypeFor[Card](): {
+			ByClass("video", VideoCard{}),
+			ByAttr("data-type", "article", &ArticleCard{}),
+			BySelector(".ad", ""),
+		},
+	}
+	type Feed struct {
+		First Card   `select:".card"`
+		Cards []Card `select:".card"`
+	}
+	cfgs := []ScrapeCfg{
+		{
+			CaseName:       "feed",
+			mode:           Tolerant,
+			discriminators: discriminators,
+			doc:            getDoc(htmldata),
+			o:              &Feed{},
+			exp: &Feed{
+				First: VideoCard{Video: "a.mp4"},
+				Cards: []Card{VideoCard{Video: "a.mp4"}, &ArticleCard{Title: "News"}, nil},
+			},
+			eErr: ScrapeErr{ScrapingErr{Cause: ScrapingErr{Selector: ".card:n(2)", Cause: ImplementErr{Type: reflect.TypeFor[string](), Interface: reflect.TypeFor[Card]()}}}},
+		},
+		{
+			CaseName: "no discriminator",
+			doc:      getDoc(htmldata),
+			o:        &Feed{},
+			exp:      &Feed{},
+			eErr:     ScrapeErr{ScrapingErr{Cause: ScrapingErr{Selector: ".card", Cause: NoDiscriminatorErr{Interface: reflect.TypeFor[Card]()}}}},
+		},
+	}
+	tab.RunWithCfgs(t, cfgs, test)
+}
+
+func TestScraper_Scrape_ScrapeArray(t *testing.T) {
+	htmldata := `<table><tr><td>a</td><td>b</td></tr><tr><td>c</td></tr></table><img src="1"><img src="2"><img src="3">`
+	type Image struct {
+		Src string `extract:"@src"`
+	}
+	type Page struct {
+		Matrix [][]string `select:"tr;td" extract:"text"`
+		Images [2]Image   `select:"img"`
+		Cells  [4]string  `select:"td" extract:"text"`
+	}
+	type StrictPage struct {
+		Images [2]Image  `select:"img" overflow:"error"`
+		Cells  [4]string `select:"td" extract:"text" underflow:"error"`
+	}
+	cfgs := []ScrapeCfg{
+		{
+			CaseName: "arrays and nested slices",
+			doc:      getDoc(htmldata),
+			o:        &Page{},
+			exp: &Page{
+				Matrix: [][]string{{"a", "b"}, {"c"}},
+				Images: [2]Image{{"1"}, {"2"}},
+				Cells:  [4]string{"a", "b", "c"},
+			},
+		},
+		{
+			CaseName: "size errors",
+			mode:     Tolerant,
+			doc:      getDoc(htmldata),
+			o:        &StrictPage{},
+			exp:      &StrictPage{},
+			eErr: ScrapeErr{errors.Join(
+				ScrapingErr{Cause: ScrapingErr{Selector: "img", Cause: ArraySizeErr{Len: 2, Found: 3}}},
+				ScrapingErr{Cause: ScrapingErr{Selector: "td", Cause: ArraySizeErr{Len: 4, Found: 3}}},
+			)},
+		},
+		{
+			CaseName: "top-level array",
+			doc:      getDoc(htmldata),
+			o:        &[2]string{},
+			selector: "td",
+			extract:  "text",
+			exp:      &[2]string{"a", "b"},
+		},
+	}
+	tab.RunWithCfgs(t, cfgs, test)
+}
+
+func TestScraper_Scrape_ScrapeMetadata(t *testing.T) {
+	htmldata := `<div class="product"><h2>A</h2><i class="review"></i><i class="review"></i><b class="sold-out"></b></div>` +
+		`<div class="product"><h2>B</h2></div>`
+	type Product struct {
+		Index   int    `extract:"index"`
+		Tag     string `select:"h2" extract:"tag"`
+		SoldOut bool   `select:".sold-out" extract:"exists"`
+		Reviews int    `select:".review" extract:"count"`
+		PSold   *bool  `select:".sold-out" extract:"exists"`
+		PCount  *int   `select:".review" extract:"count"`
+	}
+	yes, no, two, zero := true, false, 2, 0
+	type Values struct {
+		Count  uint8   `select:".rating" extract:"text"`
+		Rating float64 `select:".rating" extract:"@data-value"`
+		New    bool    `select:".rating" extract:"@data-new"`
+		Broken int     `select:".rating" extract:"@data-value"`
+	}
+	cfgs := []ScrapeCfg{
+		{
+			CaseName: "metadata",
+			doc:      getDoc(htmldata),
+			o:        &[]Product{},
+			selector: ".product",
+			exp: &[]Product{
+				{Index: 0, Tag: "h2", SoldOut: true, Reviews: 2, PSold: &yes, PCount: &two},
+				{Index: 1, Tag: "h2", SoldOut: false, Reviews: 0, PSold: &no, PCount: &zero},
+			},
+		},
+		{
+			CaseName: "parsed values",
+			mode:     Tolerant,
+			doc:      getDoc(`<p class="rating" data-value="4.5" data-new="true"> 12 </p>`),
+			o:        &Values{},
+			exp:      &Values{Count: 12, Rating: 4.5, New: true},
+			eErr: ScrapeErr{ScrapingErr{Cause: ScrapingErr{
+				Selector: ".rating",
+				Cause:    &strconv.NumError{Func: "ParseInt", Num: "4.5", Err: strconv.ErrSyntax},
+			}}},
+		},
+	}
+	tab.RunWithCfgs(t, cfgs, test)
+}
+
+func TestScraper_Scrape_ScrapeURL(t *testing.T) {
+	htmldata := `<a class="p" href="/p/123">A</a><img src="../img.png">`
+	docURL, _ := url.Parse("https://shop.com/catalog/list")
+	doc := getDoc(htmldata)
+	doc.Url = docURL
+	baseDoc := getDoc(`<head><base href="https://cdn.shop.com/static/"></head><body>` + htmldata + `</body>`)
+	parsed := func(s string) *url.URL {
+		u, _ := url.Parse(s)
+		return u
+	}
+	type Links struct {
+		Href   string   `select:"a" extract:"url:@href"`
+		Src    string   `select:"img" extract:"url:@src"`
+		Link   url.URL  `select:"a" extract:"@href"`
+		Image  *url.URL `select:"img" extract:"@src"`
+		RawSrc string   `select:"img" extract:"@src"`
+	}
+	type Gallery struct {
+		Best       string           `select:"picture" extract:"url:image"`
+		Image      string           `select:"picture" extract:"image"`
+		Candidates []ImageCandidate `select:"picture"`
+	}
+	type Article struct {
+		Body string `select:".body" extract:"markdown"`
+	}
+	article := getDoc(`<div class="body"><p>See <a href="/p/123">A</a></p></div>`)
+	article.Url = docURL
+	cfgs := []ScrapeCfg{
+		{
+			CaseName: "document url",
+			doc:      doc,
+			o:        &Links{},
+			exp: &Links{
+				Href:   "https://shop.com/p/123",
+				Src:    "https://shop.com/img.png",
+				Link:   *parsed("https://shop.com/p/123"),
+				Image:  parsed("https://shop.com/img.png"),
+				RawSrc: "../img.png",
+			},
+		},
+		{
+			CaseName: "base element",
+			doc:      baseDoc,
+			o:        &Links{},
+			exp: &Links{
+				Href:   "https://cdn.shop.com/p/123",
+				Src:    "https://cdn.shop.com/img.png",
+				Link:   *parsed("https://cdn.shop.com/p/123"),
+				Image:  parsed("https://cdn.shop.com/img.png"),
+				RawSrc: "../img.png",
+			},
+		},
+		{
+			CaseName: "scraper base url",
+			baseURL:  parsed("https://m.shop.com/a/b"),
+			doc:      doc,
+			o:        &Links{},
+			exp: &Links{
+				Href:   "https://m.shop.com/p/123",
+				Src:    "https://m.shop.com/img.png",
+				Link:   *parsed("https://m.shop.com/p/123"),
+				Image:  parsed("https://m.shop.com/img.png"),
+				RawSrc: "../img.png",
+			},
+		},
+		{
+			CaseName: "images",
+			doc: getDoc(`<picture><source srcset="/a.webp 1x, /b.webp 2x">` +
+				`<img src="/a.jpg"></picture>`),
+			baseURL: parsed("https://shop.com"),
+			o:       &Gallery{},
+			exp: &Gallery{
+				Best:  "https://shop.com/b.webp",
+				Image: "https://shop.com/b.webp",
+				Candidates: []ImageCandidate{
+					{URL: "https://shop.com/a.webp", Density: 1},
+					{URL: "https://shop.com/b.webp", Density: 2},
+					{URL: "https://shop.com/a.jpg"},
+				},
+			},
+		},
+		{
+			CaseName: "images and base element",
+			doc:      getDoc(`<base href="https://x.com/a/"><picture><img src="b.png"></picture>`),
+			o:        &Gallery{},
+			exp: &Gallery{
+				Best:       "https://x.com/a/b.png",
+				Image:      "https://x.com/a/b.png",
+				Candidates: []ImageCandidate{{URL: "https://x.com/a/b.png"}},
+			},
+		},
+		{
+			CaseName: "markdown",
+			doc:      article,
+			o:        &Article{},
+			exp:      &Article{Body: "See [A](https://shop.com/p/123)"},
+		},
+		{
+			CaseName: "markdown with scraper base url",
+			baseURL:  parsed("https://m.shop.com/a/b"),
+			doc:      article,
+			o:        &Article{},
+			exp:      &Article{Body: "See [A](https://m.shop.com/p/123)"},
+		},
+		{
+			CaseName: "no base url",
+			doc:      getDoc(htmldata),
+			o:        &Links{},
+			exp: &Links{
+				Href:   "/p/123",
+				Src:    "../img.png",
+				Link:   *parsed("/p/123"),
+				Image:  parsed("../img.png"),
+				RawSrc: "../img.png",
+			},
+		},
+	}
+	tab.RunWithCfgs(t, cfgs, test)
+}
+
+func TestScraper_Scrape_ScrapeAttributes(t *testing.T) {
+	htmldata := `<div class="product sale" id="p1" style="color: red" data-product-id="12" data-stock="true"></div>`
+	type Product struct {
+		ID      int               `data:"product-id"`
+		InStock bool              `data:"stock"`
+		Attrs   map[string]string `extract:"attrs"`
+		Data    map[string]string `extract:"data"`
+		Style   map[string]string `extract:"style"`
+		Classes []string          `extract:"classes"`
+	}
+	type Invalid struct {
+		Classes string `extract:"classes"`
+	}
+	type Folded struct {
+		ViewBox string   `select:"svg" extract:"@viewbox"`
+		Boxes   []string `select:"symbol" where:"@VIEWBOX" extract:"@viewBox"`
+		InStock bool     `select:"p" data:"In-Stock"`
+	}
+	cfgs := []ScrapeCfg{
+		{
+			CaseName: "attributes",
+			doc:      getDoc(htmldata),
+			o:        &Product{},
+			selector: ".product",
+			exp: &Product{
+				ID:      12,
+				InStock: true,
+				Attrs: map[string]string{"class": "product sale", "id": "p1", "style": "color: red",
+					"data-product-id": "12", "data-stock": "true"},
+				Data:    map[string]string{"product-id": "12", "stock": "true"},
+				Style:   map[string]string{"color": "red"},
+				Classes: []string{"product", "sale"},
+			},
+		},
+		{
+			CaseName: "type mismatch",
+			doc:      getDoc(htmldata),
+			o:        &Invalid{},
+			selector: ".product",
+			exp:      &Invalid{},
+			eErr:     ScrapeErr{ScrapingErr{Selector: ".product", Cause: TypeErr{Exp: reflect.TypeFor[string](), Act: reflect.TypeFor[[]string]()}}},
+		},
+		{
+			CaseName:       "ignore attribute case",
+			ignoreAttrCase: true,
+			doc:            getDoc(`<svg viewBox="0 0 24 24"><symbol viewBox="0 0 8 8"></symbol><symbol></symbol></svg><p data-in-stock="true"></p>`),
+			o:              &Folded{},
+			exp:            &Folded{ViewBox: "0 0 24 24", Boxes: []string{"0 0 8 8"}, InStock: true},
+		},
+	}
+	tab.RunWithCfgs(t, cfgs, test)
+}
+
+func TestScraper_Scrape_ScrapeForeign(t *testing.T) {
+	htmldata := `<svg><defs><linearGradient id="g1"><stop offset="0"></stop></linearGradient></defs>` +
+		`<use xlink:href="#icon-cart"></use><use href="#icon-user"></use></svg><p class="md:flex clippath-icon">Cart</p>`
+	type Icon struct {
+		Gradient string   `select:"linearGradient" extract:"@id"`
+		Stops    int      `select:"defs linearGradient > stop" extract:"count"`
+		Icons    []string `select:"use" extract:"@xlink:href"`
+		Linked   string   `select:"[xlink\\:href]" extract:"@xlink:href"`
+		Caption  string   `select:".md\\:flex.clippath-icon" extract:"text"`
+	}
+	type Relative struct {
+		Gradient string   `select:"> defs > linearGradient" extract:"@id"`
+		Stops    int      `select:"> defs > linearGradient > stop" extract:"count"`
+		Linked   []string `select:"use" where:"is([xlink\\:href])" extract:"@xlink:href"`
+	}
+	cfgs := []ScrapeCfg{
+		{
+			CaseName: "svg",
+			mode:     Tolerant,
+			doc:      getDoc(htmldata),
+			o:        &Icon{},
+			exp:      &Icon{Gradient: "g1", Stops: 1, Icons: []string{"#icon-cart", ""}, Linked: "#icon-cart", Caption: "Cart"},
+			eErr: ScrapeErr{ScrapingErr{Cause: ScrapingErr{
+				Selector: "use:n(1)",
+				Cause:    AttributeNotFoundErr{Attr: "xlink:href"},
+			}}},
+		},
+		{
+			CaseName: "relative selectors and filters",
+			doc:      getDoc(htmldata),
+			o:        &Relative{},
+			selector: "svg",
+			exp:      &Relative{Gradient: "g1", Stops: 1, Linked: []string{"#icon-cart"}},
+		},
+	}
+	tab.RunWithCfgs(t, cfgs, test)
+}
+
+func TestScraper_Scrape_ScrapeContext(t *testing.T) {
+	htmldata := `<base href="https://shop.com/catalog/"><div class="tags"><a href="go">Go</a><a href="web">Web</a></div>`
+
+	extractors := map[*Match]ContextExtractor{}
+	joinMatch := GetEqualMatch("join")
+	extractors[&joinMatch] = func(ec ExtractContext) (string, error) {
+		return strings.Join(ec.Selection.Map(func(_ int, s *goquery.Selection) string { return s.Text() }), ", "), nil
+	}
+	linksMatch := GetEqualMatch("links")
+	extractors[&linksMatch] = func(ec ExtractContext) (string, error) {
+		links := []string{}
+		for _, href := range ec.Selection.Map(func(_ int, s *goquery.Selection) string { return s.AttrOr("href", "") }) {
+			u, err := ec.BaseURL.Parse(href)
+			if err != nil {
+				return "", err
+			}
+			links = append(links, u.String())
+		}
+		return strings.Join(links, " "), nil
+	}
+	fieldMatch := GetEqualMatch("field")
+	extractors[&fieldMatch] = func(ec ExtractContext) (string, error) {
+		return ec.Field.Name + ":" + ec.Type.String() + ":" + ec.Field.Tag.Get("unit"), nil
+	}
+
+	type Tags struct {
+		Names string   `select:"a" extract:"join"`
+		Links string   `select:"a" extract:"links"`
+		Field string   `select:"a" extract:"field" unit:"px"`
+		Many  []string `select:"a" extract:"field" unit:"em"`
+	}
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	cfgs := []ScrapeCfg{
+		{
+			CaseName:      "selection, base url and field",
+			ctxExtractors: extractors,
+			doc:           getDoc(htmldata),
+			o:             &Tags{},
+			exp: &Tags{
+				Names: "Go, Web",
+				Links: "https://shop.com/catalog/go https://shop.com/catalog/web",
+				Field: "Field:string:px",
+				Many:  []string{"Many:string:em", "Many:string:em"},
+			},
+		},
+		{
+			CaseName:      "canceled context",
+			ctx:           canceled,
+			ctxExtractors: extractors,
+			doc:           getDoc(htmldata),
+			o:             &Tags{},
+			exp:           &Tags{},
+			eErr:          ScrapeErr{context.Canceled},
+		},
+	}
+	tab.RunWithCfgs(t, cfgs, test)
+}
+
+func TestScraper_Scrape_ScrapeTyped(t *testing.T) {
+	htmldata := `<div class="offer" data-price="9.50" data-date="2024-03-01" data-size="10x20">` +
+		`<span class="date">2024-01-02</span><span class="date">2024-02-03</span></div>`
+
+	type Size struct {
+		W, H int
+	}
+
+	extractors := map[*Match]ValueExtractor{}
+	dateMatch := GetEqualMatch("date")
+	extractors[&dateMatch] = TypedExtractor[time.Time](func(ec ExtractContext) (time.Time, error) {
+		return time.Parse(time.DateOnly, ec.Selection.First().Text())
+	})
+	priceMatch := GetPrefixMatch("price:")
+	extractors[&priceMatch] = TypedExtractor[float64](func(ec ExtractContext) (float64, error) {
+		return strconv.ParseFloat(ec.Selection.AttrOr(ec.Extract, ""), 64)
+	})
+	sizeMatch := GetEqualMatch("size")
+	extractors[&sizeMatch] = TypedExtractor[Size](func(ec ExtractContext) (Size, error) {
+		size := Size{}
+		_, err := fmt.Sscanf(ec.Selection.AttrOr("data-size", ""), "%dx%d", &size.W, &size.H)
+		return size, err
+	})
+
+	type Offer struct {
+		Price   float64      `extract:"price:data-price"`
+		Size    Size         `extract:"size"`
+		Dates   []time.Time  `select:".date" extract:"date"`
+		First   *time.Time   `select:".date" extract:"date"`
+		Updated [1]time.Time `select:".date:last-child" extract:"date"`
+	}
+	type Mismatch struct {
+		Price string `extract:"price:data-price"`
+	}
+
+	day := func(m time.Month, d int) time.Time { return time.Date(2024, m, d, 0, 0, 0, 0, time.UTC) }
+	first := day(1, 2)
+
+	cfgs := []ScrapeCfg{
+		{
+			CaseName:   "time, float and struct",
+			typedExtrs: extractors,
+			doc:        getDoc(htmldata),
+			o:          &Offer{},
+			selector:   ".offer",
+			exp: &Offer{
+				Price:   9.5,
+				Size:    Size{W: 10, H: 20},
+				Dates:   []time.Time{day(1, 2), day(2, 3)},
+				First:   &first,
+				Updated: [1]time.Time{day(2, 3)},
+			},
+		},
+		{
+			CaseName:   "type mismatch",
+			typedExtrs: extractors,
+			doc:        getDoc(htmldata),
+			o:          &Mismatch{},
+			selector:   ".offer",
+			exp:        &Mismatch{},
+			eErr: ScrapeErr{ScrapingErr{Selector: ".offer", Cause: ScrapingErr{
+				Cause: TypeErr{Exp: reflect.TypeFor[string](), Act: reflect.TypeFor[float64]()},
+			}}},
+		},
+		{
+			CaseName:   "extractor error",
+			typedExtrs: extractors,
+			doc:        getDoc(`<span class="date">soon</span>`),
+			o:          &time.Time{},
+			selector:   ".date",
+			extract:    "date",
+			exp:        &time.Time{},
+			eErr: ScrapeErr{ScrapingErr{Selector: ".date", Cause: &time.ParseError{
+				Layout: time.DateOnly, Value: "soon", LayoutElem: "2006", ValueElem: "soon",
+			}}},
+		},
+	}
+	tab.RunWithCfgs(t, cfgs, test)
+}
+
+func TestScraper_Scrape_ScrapeNumber(t *testing.T) {
+	htmldata := `<div class="product"><span class="price">1.299,99 €</span><span class="old">1.499,00 €</span>` +
+		`<span class="stock">1.204 Stück</span><span class="rating">4,5</span><span class="rating">3,0</span></div>`
+	type Product struct {
+		Price   Money     `select:".price" extract:"text" number:"de-DE"`
+		Old     *Money    `select:".old" extract:"text" number:"de-DE"`
+		Stock   int       `select:".stock" extract:"text" number:"de-DE"`
+		Ratings []float64 `select:".rating" extract:"text" number:"de"`
+	}
+	type Detected struct {
+		Price Money `select:".price" extract:"text"`
+	}
+	type Invalid struct {
+		Stock int `select:".rating" extract:"text" number:"de"`
+	}
+	old := Money{Amount: 1499, Currency: "EUR"}
+	cfgs := []ScrapeCfg{
+		{
+			CaseName: "locale",
+			doc:      getDoc(htmldata),
+			o:        &Product{},
+			selector: ".product",
+			exp: &Product{
+				Price:   Money{Amount: 1299.99, Currency: "EUR"},
+				Old:     &old,
+				Stock:   1204,
+				Ratings: []float64{4.5, 3},
+			},
+		},
+		{
+			CaseName: "detected separators",
+			doc:      getDoc(htmldata),
+			o:        &Detected{},
+			selector: ".product",
+			exp:      &Detected{Price: Money{Amount: 1299.99, Currency: "EUR"}},
+		},
+		{
+			CaseName: "fraction to int",
+			doc:      getDoc(htmldata),
+			o:        &Invalid{},
+			selector: ".product",
+			exp:      &Invalid{},
+			eErr: ScrapeErr{ScrapingErr{Selector: ".product", Cause: ScrapingErr{
+				Selector: ".rating",
+				Cause:    &strconv.NumError{Func: "ParseInt", Num: "4.5", Err: strconv.ErrSyntax},
+			}}},
+		},
+	}
+	tab.RunWithCfgs(t, cfgs, test)
+}
+
+func TestScraper_Scrape_ScrapeTime(t *testing.T) {
+	htmldata := `<article><time datetime="2024-03-01T10:00:00Z">March 1</time>` +
+		`<span class="edited">01.03.2024 12:30</span>` +
+		`<ul><li class="review">3 hours ago</li><li class="review">yesterday</li><li class="review">Feb 2, 2024</li></ul></article>`
+	type Post struct {
+		Published time.Time   `select:"time"`
+		Label     time.Time   `select:"time" extract:"text" time:"January 2"`
+		Edited    *time.Time  `select:".edited" extract:"text" time:"02.01.2006 15:04" timezone:"Europe/Berlin"`
+		Reviews   []time.Time `select:".review" time:"Jan 2, 2006"`
+	}
+	type Invalid struct {
+		Edited time.Time `select:".edited" timezone:"Mars/Olympus"`
+	}
+	now := time.Date(2024, 3, 10, 15, 0, 0, 0, time.UTC)
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	edited := time.Date(2024, 3, 1, 12, 30, 0, 0, berlin)
+	cfgs := []ScrapeCfg{
+		{
+			CaseName: "layouts, datetime and relative times",
+			clock:    func() time.Time { return now },
+			doc:      getDoc(htmldata),
+			o:        &Post{},
+			selector: "article",
+			exp: &Post{
+				Published: time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC),
+				Label:     time.Date(0, 3, 1, 0, 0, 0, 0, time.UTC),
+				Edited:    &edited,
+				Reviews: []time.Time{
+					time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC),
+					time.Date(2024, 3, 9, 0, 0, 0, 0, time.UTC),
+					time.Date(2024, 2, 2, 0, 0, 0, 0, time.UTC),
+				},
+			},
+		},
+		{
+			CaseName: "unknown time zone",
+			doc:      getDoc(htmldata),
+			o:        &Invalid{},
+			selector: "article",
+			exp:      &Invalid{},
+			eErr: ScrapeErr{ScrapingErr{Selector: "article", Cause: ScrapingErr{
+				Selector: ".edited",
+				Cause:    errors.New("unknown time zone Mars/Olympus"),
+			}}},
+		},
+	}
+	tab.RunWithCfgs(t, cfgs, test)
+}
+
+func TestScraper_Scrape_ScrapeEnum(t *testing.T) {
+	htmldata := `<div class="product"><span class="stock"> IN  STOCK </span><span class="rating">Excellent</span>` +
+		`<span class="label">Only 3 left, hurry</span><span class="label">Pre-order</span><span class="label">New</span></div>`
+	type Product struct {
+		Stock  string   `select:".stock" extract:"text" enum:"In stock=available,Out of stock=soldout,*=unknown"`
+		Rating int      `select:".rating" extract:"text" enum:"poor=1, good=4, excellent=5"`
+		Labels []string `select:".label" extract:"text" enum:"/^only \\d+ left, hurry$/=few,/^pre-?order$/=preorder,*=other"`
+	}
+	type Unmapped struct {
+		Stock  string `select:".stock" extract:"text" enum:"Out of stock=soldout"`
+		Rating int    `select:".rating" extract:"text" enum:"excellent=5"`
+	}
+	type InvalidTag struct {
+		Stock string `select:".stock" extract:"text" enum:"/[/=x"`
+	}
+	cfgs := []ScrapeCfg{
+		{
+			CaseName: "mapped",
+			doc:      getDoc(htmldata),
+			o:        &Product{},
+			selector: ".product",
+			exp:      &Product{Stock: "available", Rating: 5, Labels: []string{"few", "preorder", "other"}},
+		},
+		{
+			CaseName: "unmapped in strict mode",
+			doc:      getDoc(htmldata),
+			o:        &Unmapped{},
+			selector: ".product",
+			exp:      &Unmapped{},
+			eErr: ScrapeErr{ScrapingErr{Selector: ".product", Cause: ScrapingErr{
+				Selector: ".stock",
+				Cause:    EnumErr{Value: " IN  STOCK "},
+			}}},
+		},
+		{
+			CaseName: "unmapped in tolerant mode",
+			mode:     Tolerant,
+			doc:      getDoc(htmldata),
+			o:        &Unmapped{},
+			selector: ".product",
+			exp:      &Unmapped{Rating: 5},
+			eErr: ScrapeErr{ScrapingErr{Selector: ".product", Cause: ScrapingErr{
+				Selector: ".stock",
+				Cause:    EnumErr{Value: " IN  STOCK "},
+			}}},
+		},
+		{
+			CaseName: "invalid tag",
+			doc:      getDoc(htmldata),
+			o:        &InvalidTag{},
+			selector: ".product",
+			exp:      &InvalidTag{},
+			eErr: ScrapeErr{ScrapingErr{Selector: ".product", Cause: ScrapingErr{
+				Selector: ".stock",
+				Cause:    EnumTagErr{Case: "/[/", Cause: errors.New("error parsing regexp: missing closing ]: `[`")},
+			}}},
+		},
+	}
+	tab.RunWithCfgs(t, cfgs, test)
+}
+
+func TestScraper_Scrape_ScrapeSplitJoin(t *testing.T) {
+	htmldata := `<div class="post"><span class="tags"> go, web ,, scraping, </span><span class="sizes">38 40 42</span>` +
+		`<a class="author">Ann</a><a class="author"> </a><a class="author">Bob</a><span class="prices">1,50; 2,00</span></div>`
+	type Post struct {
+		Tags    []string  `select:".tags" extract:"text" split:","`
+		Sizes   []int     `select:".sizes" extract:"text" split:""`
+		Prices  []float64 `select:".prices" extract:"text" split:";" number:"de"`
+		Authors string    `select:".author" extract:"text" join:", "`
+		First   string    `select:".author" extract:"text"`
+	}
+	type Invalid struct {
+		Sizes []int `select:".tags" extract:"text" split:","`
+	}
+	cfgs := []ScrapeCfg{
+		{
+			CaseName: "split and join",
+			doc:      getDoc(htmldata),
+			o:        &Post{},
+			selector: ".post",
+			exp: &Post{
+				Tags:    []string{"go", "web", "scraping"},
+				Sizes:   []int{38, 40, 42},
+				Prices:  []float64{1.5, 2},
+				Authors: "Ann, Bob",
+				First:   "Ann",
+			},
+		},
+		{
+			CaseName: "invalid part",
+			mode:     Tolerant,
+			doc:      getDoc(htmldata),
+			o:        &Invalid{},
+			selector: ".post",
+			exp:      &Invalid{Sizes: []int{0, 0, 0}},
+			eErr: ScrapeErr{ScrapingErr{Selector: ".post", Cause: errors.Join(
+				ScrapingErr{Selector: ".tags:n(0)", Cause: &strconv.NumError{Func: "ParseInt", Num: "go", Err: strconv.ErrSyntax}},
+				ScrapingErr{Selector: ".tags:n(1)", Cause: &strconv.NumError{Func: "ParseInt", Num: "web", Err: strconv.ErrSyntax}},
+				ScrapingErr{Selector: ".tags:n(2)", Cause: &strconv.NumError{Func: "ParseInt", Num: "scraping", Err: strconv.ErrSyntax}},
+			)}},
+		},
+	}
+	tab.RunWithCfgs(t, cfgs, test)
+}
+
+func TestScraper_Scrape_ScrapeWhere(t *testing.T) {
+	htmldata := `<ul>` +
+		`<li data-stock="yes" data-sku="A-1"><span class="name">Apple</span><span class="price">1</span></li>` +
+		`<li data-stock="no" data-sku="B-2"><span class="name">Banana</span><span class="price">2</span></li>` +
+		`<li data-stock="yes" data-sku="C-3"><span class="name">Cherry</span><span class="badge-sale">-10%</span></li>` +
+		`<li data-sku="D4"><span class="name">Dates sale</span><span class="price">x</span></li>` +
+		`</ul>`
+	type Item struct {
+		Name  string `select:".name" extract:"text"`
+		Index int    `extract:"index"`
+	}
+	type Price struct {
+		Price int `select:".price" extract:"text"`
+	}
+	type Catalog struct {
+		InStock  []string  `select:"li" extract:"@data-sku" where:"@data-stock=yes"`
+		Sale     []Item    `select:"li" where:"has(.badge-sale)"`
+		NotSale  []string  `select:"li .name" extract:"text" where:"!text*=sale"`
+		Pattern  []string  `select:"li" extract:"@data-sku" where:"@data-sku=~^[A-Z]-\\d$"`
+		Tracked  [2]string `select:"li" extract:"@data-sku" where:"@data-stock"`
+		Filtered []Item    `select:"li" where:"@data-stock!=no"`
+	}
+	type Errors struct {
+		Prices []Price `select:"li" where:"!@data-stock=yes"`
+	}
+	type Invalid struct {
+		Items []string `select:"li" extract:"text" where:"@data-sku=~["`
+	}
+	type Has struct {
+		SKUs     []string `select:"li" extract:"@data-sku" where:"has(.badge-sale)"`
+		Sections []string `select:"article" group:"h2" extract:"text" where:"has(.new)"`
+	}
+	cfgs := []ScrapeCfg{
+		{
+			CaseName: "filters",
+			doc:      getDoc(htmldata),
+			o:        &Catalog{},
+			exp: &Catalog{
+				InStock:  []string{"A-1", "C-3"},
+				Sale:     []Item{{Name: "Cherry"}},
+				NotSale:  []string{"Apple", "Banana", "Cherry"},
+				Pattern:  []string{"A-1", "B-2", "C-3"},
+				Tracked:  [2]string{"A-1", "B-2"},
+				Filtered: []Item{{Name: "Apple"}, {Name: "Cherry", Index: 1}, {Name: "Dates sale", Index: 2}},
+			},
+		},
+		{
+			CaseName: "indexes of filtered items in errors",
+			mode:     Tolerant,
+			doc:      getDoc(htmldata),
+			o:        &Errors{},
+			exp:      &Errors{Prices: []Price{{Price: 2}, {}}},
+			eErr: ScrapeErr{ScrapingErr{Cause: ScrapingErr{Selector: "li:n(1)", Cause: ScrapingErr{
+				Selector: ".price",
+				Cause:    &strconv.NumError{Func: "ParseInt", Num: "x", Err: strconv.ErrSyntax},
+			}}}},
+		},
+		{
+			CaseName: "invalid clause",
+			doc:      getDoc(htmldata),
+			o:        &Invalid{},
+			exp:      &Invalid{},
+			eErr: ScrapeErr{ScrapingErr{Cause: ScrapingErr{Selector: "li", Cause: WhereTagErr{
+				Clause: "@data-sku=~[",
+				Cause:  errors.New("error parsing regexp: missing closing ]: `[`"),
+			}}}},
+		},
+		{
+			CaseName: "has descendants of items and nodes of groups",
+			doc: getDoc(`<ul><li class="badge-sale" data-sku="X">x</li><li data-sku="Y"><i class="badge-sale"></i></li></ul>` +
+				`<article><h2>A</h2><p class="new">a</p><h2>B</h2><p>b</p></article>`),
+			o:   &Has{},
+			exp: &Has{SKUs: []string{"Y"}, Sections: []string{"A"}},
+		},
+	}
+	tab.RunWithCfgs(t, cfgs, test)
+}
+
+func TestScraper_Scrape_ScrapeArrange(t *testing.T) {
+	htmldata := `<div class="carousel">` +
+		`<div class="product" data-id="2"><a href="/b">Banana</a><span>2.50</span></div>` +
+		`<div class="product" data-id="1"><a href="/a">Apple</a><span>1.00</span></div>` +
+		`</div><div class="grid">` +
+		`<div class="product" data-id="1"><a href="/a">Apple</a><span>1.00</span></div>` +
+		`<div class="product" data-id="3"><a href="/c">Cherry</a><span>4.00</span></div>` +
+		`<div class="product" data-id="2"><a href="/b">Banana</a><span>2.50</span></div>` +
+		`<div class="product" data-id="4"><a href="/d">Date</a><span>1.00</span></div>` +
+		`</div>`
+	type Product struct {
+		ID    int     `data:"id"`
+		Name  string  `select:"a" extract:"text"`
+		Price float64 `select:"span" extract:"text"`
+	}
+	type Catalog struct {
+		Unique   []Product  `select:".product" unique:""`
+		ByID     []*Product `select:".product" unique:"ID" sort:"Price desc, Name"`
+		Cheapest []Product  `select:".product" unique:"ID" sort:"Price asc" limit:"2"`
+		Names    []string   `select:".product a" extract:"text" unique:"" sort:"desc"`
+		First    []string   `select:".product a" extract:"@href" limit:"3"`
+	}
+	type Invalid struct {
+		Products []Product `select:".product" sort:"Weight"`
+	}
+	type Mixed struct {
+		Values []any `select:"i" extract:"mixed" sort:""`
+	}
+	mixedMatch := GetEqualMatch("mixed")
+	typed := map[*Match]ValueExtractor{&mixedMatch: TypedExtractor[any](func(ec ExtractContext) (any, error) {
+		val := ec.Selection.AttrOr("data-v", "")
+		if n, err := strconv.Atoi(val); err == nil {
+			return n, nil
+		}
+		return val, nil
+	})}
+	apple, banana := Product{ID: 1, Name: "Apple", Price: 1}, Product{ID: 2, Name: "Banana", Price: 2.5}
+	cherry, date := Product{ID: 3, Name: "Cherry", Price: 4}, Product{ID: 4, Name: "Date", Price: 1}
+	cfgs := []ScrapeCfg{
+		{
+			CaseName: "unique, sort and limit",
+			doc:      getDoc(htmldata),
+			o:        &Catalog{},
+			exp: &Catalog{
+				Unique:   []Product{banana, apple, cherry, date},
+				ByID:     []*Product{&cherry, &banana, &apple, &date},
+				Cheapest: []Product{apple, date},
+				Names:    []string{"Date", "Cherry", "Banana", "Apple"},
+				First:    []string{"/b", "/a", "/a"},
+			},
+		},
+		{
+			CaseName: "unknown field",
+			mode:     Tolerant,
+			doc:      getDoc(htmldata),
+			o:        &Invalid{},
+			exp:      &Invalid{Products: []Product{banana, apple, apple, cherry, banana, date}},
+			eErr: ScrapeErr{ScrapingErr{Cause: ScrapingErr{Selector: ".product", Cause: SliceTagErr{
+				Tag:   SortTag,
+				Value: "Weight",
+				Cause: errors.New("scrape_test.Product has no field Weight"),
+			}}}},
+		},
+		{
+			CaseName:   "values of different types",
+			mode:       Tolerant,
+			typedExtrs: typed,
+			doc:        getDoc(`<i data-v="x"></i><i data-v="1"></i>`),
+			o:          &Mixed{},
+			exp:        &Mixed{Values: []any{"x", 1}},
+			eErr: ScrapeErr{ScrapingErr{Cause: ScrapingErr{Selector: "i", Cause: SliceTagErr{
+				Tag:   SortTag,
+				Cause: errors.New("int and string cannot be compared"),
+			}}}},
+		},
+	}
+	tab.RunWithCfgs(t, cfgs, test)
+}
+
+func TestScraper_Scrape_ScrapeTemplate(t *testing.T) {
+	htmldata := `<base href="https://shop.com/"><div class="product" data-brand="Acme" data-size="XL" data-sku="42">` +
+		` Hoodie <a href="p/42">more</a><span class="color"> red </span></div>`
+	type Product struct {
+		Title string `extract:"tmpl:{@data-brand} {text} ({@data-size})"`
+		Color string `extract:"tmpl:{text}, {.color | text}"`
+		ID    string `extract:"tmpl:{{{@data-sku}}}"`
+		Link  string `extract:"tmpl:{a | url:@href}#{> .color | text}"`
+	}
+	type Missing struct {
+		Price string `extract:"tmpl:{.price | text} {@data-currency}"`
+	}
+	type Invalid struct {
+		Title string `extract:"tmpl:{@data-brand"`
+	}
+	cfgs := []ScrapeCfg{
+		{
+			CaseName: "attributes, text and children",
+			doc:      getDoc(htmldata),
+			o:        &Product{},
+			selector: ".product",
+			exp: &Product{
+				Title: "Acme Hoodie (XL)",
+				Color: "Hoodie, red",
+				ID:    "{42}",
+				Link:  "https://shop.com/p/42#red",
+			},
+		},
+		{
+			CaseName: "missing child",
+			doc:      getDoc(htmldata),
+			o:        &Missing{},
+			selector: ".product",
+			exp:      &Missing{},
+			eErr: ScrapeErr{ScrapingErr{Selector: ".product", Cause: ScrapingErr{
+				Cause: ScrapingErr{Selector: ".price", Cause: NoNodesFoundErr{}},
+			}}},
+		},
+		{
+			CaseName: "unmatched brace",
+			doc:      getDoc(htmldata),
+			o:        &Invalid{},
+			selector: ".product",
+			exp:      &Invalid{},
+			eErr: ScrapeErr{ScrapingErr{Selector: ".product", Cause: ScrapingErr{
+				Cause: TemplateErr{Template: "{@data-brand", Cause: errors.New("unmatched brace")},
+			}}},
+		},
+	}
+	tab.RunWithCfgs(t, cfgs, test)
+}
+
+func TestScraper_Scrape_ScrapeJSON(t *testing.T) {
+	htmldata := `<h1>Hoodie</h1>` +
+		`<div class="product" data-props='{"sku": "H-1", "sizes": ["M", "L"]}'></div>` +
+		`<script id="__NEXT_DATA__" type="application/json">` +
+		`{"props": {"pageProps": {"product": {"price": 49.9, "stock": 12, "tags": ["warm", "cotton"],` +
+		`"seller": {"name": "Acme", "rating": 4.5}, "discount": null}}}}</script>` +
+		`<script>window.__STATE__ = {user: {name: 'Ann', id: 7},};</script>`
+	type Seller struct {
+		Name   string  `json:"name"`
+		Rating float64 `json:"rating"`
+	}
+	type Product struct {
+		Name     string   `select:"h1" extract:"text"`
+		Price    float64  `select:"script#__NEXT_DATA__" jsonpath:"props.pageProps.product.price"`
+		Stock    *int     `select:"script#__NEXT_DATA__" jsonpath:"props.pageProps.product.stock"`
+		Tags     []string `select:"script#__NEXT_DATA__" jsonpath:"props.pageProps.product.tags"`
+		FirstTag string   `select:"script#__NEXT_DATA__" jsonpath:"props.pageProps.product.tags[0]"`
+		Seller   Seller   `select:"script#__NEXT_DATA__" jsonpath:"props.pageProps.product.seller"`
+		Raw      string   `select:"script#__NEXT_DATA__" jsonpath:"props.pageProps.product.seller"`
+		Discount string   `select:"script#__NEXT_DATA__" jsonpath:"props.pageProps.product.discount"`
+		User     string   `select:"script:not([id])" jsonpath:"user.name"`
+		Sizes    []string `select:".product" extract:"@data-props" jsonpath:"sizes"`
+	}
+	type Missing struct {
+		Price float64 `select:"script#__NEXT_DATA__" jsonpath:"props.product.price"`
+		Title string  `select:"h1" jsonpath:""`
+	}
+	stock := 12
+	cfgs := []ScrapeCfg{
+		{
+			CaseName: "json paths and selectors",
+			doc:      getDoc(htmldata),
+			o:        &Product{},
+			exp: &Product{
+				Name:     "Hoodie",
+				Price:    49.9,
+				Stock:    &stock,
+				Tags:     []string{"warm", "cotton"},
+				FirstTag: "warm",
+				Seller:   Seller{Name: "Acme", Rating: 4.5},
+				Raw:      `{"name":"Acme","rating":4.5}`,
+				User:     "Ann",
+				Sizes:    []string{"M", "L"},
+			},
+		},
+		{
+			CaseName: "missing key and json",
+			mode:     Tolerant,
+			doc:      getDoc(htmldata),
+			o:        &Missing{},
+			exp:      &Missing{},
+			eErr: ScrapeErr{ScrapingErr{Cause: errors.Join(
+				ScrapingErr{Selector: "script#__NEXT_DATA__", Cause: JSONPathErr{Path: "props.product.price", Key: "product"}},
+				ScrapingErr{Selector: "h1", Cause: JSONNotFoundErr{}},
+			)}},
+		},
 	}
 	tab.RunWithCfgs(t, cfgs, test)
 }
@@ -211,6 +1482,9 @@ func TestScraper_Scrape_ScrapeStruct(t *testing.T) {
 
 func TestScraper_Scrape_ScrapeString(t *testing.T) {
 	s1, s2, s3 := "", "", "golang"
+	s4 := `<div class="con"><b>golang</b></div>`
+	s5, s6, s7, s8 := "", `<p><b>go</b> <a href="/go">lang</a></p>`, "", `go <a href="/go">lang</a>`
+	htmldata := `<div class="post"><p onclick="run()"><b>go</b> <a href="/go" target="_blank">lang</a></p></div>`
 	cfgs := []ScrapeCfg{
 		{
 			CaseName: "silent mod",
@@ -256,6 +1530,14 @@ func TestScraper_Scrape_ScrapeString(t *testing.T) {
 			extract:  TextExtractTag,
 			exp:      &s3,
 		},
+		{
+			CaseName: "inner html",
+			doc:      getDoc(`<div id="top"><div class="con"><b>golang</b></div></div>`),
+			o:        &s1,
+			selector: "#top",
+			extract:  HTMLExtractTag,
+			exp:      &s4,
+		},
 		{
 			CaseName: "attribute",
 			doc:      getDoc(`<div id="top"><div class="con" data="golang">text</div></div>`),
@@ -264,6 +1546,23 @@ func TestScraper_Scrape_ScrapeString(t *testing.T) {
 			extract:  "@data",
 			exp:      &s3,
 		},
+		{
+			CaseName: "default sanitizer",
+			doc:      getDoc(htmldata),
+			o:        &s5,
+			selector: ".post",
+			extract:  SafeHTMLExtractTag,
+			exp:      &s6,
+		},
+		{
+			CaseName:  "custom sanitizer",
+			sanitizer: &Sanitizer{Elements: map[string][]string{"a": {"href"}}},
+			doc:       getDoc(htmldata),
+			o:         &s7,
+			selector:  ".post",
+			extract:   SafeHTMLExtractTag,
+			exp:       &s8,
+		},
 	}
 	tab.RunWithCfgs(t, cfgs, test)
 }
@@ -286,7 +1585,7 @@ func TestScraper_Scrape_CommonErrors(t *testing.T) {
 			doc:      getDoc(""),
 			o:        &map[int]int{},
 			exp:      &map[int]int{},
-			eErr:     ScrapeErr{KindErr{"o", []any{"string", "slice", "struct", "ptr"}, "map"}},
+			eErr:     ScrapeErr{KindErr{"o", []any{"string", "bool", "int", "uint", "float64", "slice", "array", "struct", "ptr", "interface"}, "map"}},
 		},
 	}
 	tab.RunWithCfgs(t, cfgs, test)
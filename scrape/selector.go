@@ -0,0 +1,138 @@
+package scrape
+
+import (
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// isRelativeSelector reports whether any selector of the group starts with
+// a combinator ("> .replies", "+ p", "~ li") and so is relative to the
+// current nodes.
+func isRelativeSelector(selector string) bool {
+	for _, selector := range splitSelectors(selector) {
+		selector = strings.TrimSpace(selector)
+		if len(selector) != 0 && strings.ContainsRune(">+~", rune(selector[0])) {
+			return true
+		}
+	}
+	return false
+}
+
+// findRelative returns the nodes matched by a relative selector starting
+// from the nodes of the selection. Every combinator is applied one by one:
+// ">" to the children, " " to the descendants, "+" to the next sibling,
+// and "~" to all the following siblings. The nodes matched by every
+// selector of a group ("> li, > p") are combined.
+func findRelative(ft *foreignTree, selection *goquery.Selection, selector string) *goquery.Selection {
+	found := emptySelection(selection)
+	for _, selector := range splitSelectors(selector) {
+		found = found.AddSelection(findSteps(ft, selection, selector))
+	}
+	return found
+}
+
+// findSteps returns the nodes matched by a single relative selector
+// starting from the nodes of the selection.
+func findSteps(ft *foreignTree, selection *goquery.Selection, selector string) *goquery.Selection {
+	for _, step := range splitCombinators(selector) {
+		switch step.combinator {
+		case '>':
+			selection = filterSelector(ft, selection.Children(), step.compound)
+		case '+':
+			selection = filterSelector(ft, selection.Next(), step.compound)
+		case '~':
+			selection = filterSelector(ft, selection.NextAll(), step.compound)
+		default:
+			selection = findSelector(ft, selection, step.compound)
+		}
+	}
+	return selection
+}
+
+// emptySelection returns an empty selection that nodes can be added to.
+// Unlike selection.Slice(0, 0), it does not share the array of the nodes
+// with the selection, so adding nodes does not overwrite them.
+func emptySelection(selection *goquery.Selection) *goquery.Selection {
+	return selection.FilterNodes()
+}
+
+// splitSelectors splits the selector group into selectors by the commas
+// that are not enclosed in brackets, parentheses, or quotes.
+func splitSelectors(group string) []string {
+	return splitOutside(group, ',')
+}
+
+// splitOutside splits the selector by the separators that are not enclosed
+// in brackets, parentheses, or quotes.
+func splitOutside(selector string, separator byte) []string {
+	parts := []string{}
+	depth, quote, start := 0, byte(0), 0
+	for i := 0; i < len(selector); i++ {
+		c := selector[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[' || c == '(':
+			depth++
+		case c == ']' || c == ')':
+			depth--
+		case depth == 0 && c == separator:
+			parts = append(parts, selector[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, selector[start:])
+}
+
+// selectorStep is a compound selector with the combinator before it.
+type selectorStep struct {
+	combinator byte
+	compound   string
+}
+
+// splitCombinators splits the selector into compound selectors by the
+// combinators that are not enclosed in brackets, parentheses, or quotes.
+func splitCombinators(selector string) []selectorStep {
+	steps := []selectorStep{}
+	var combinator byte = ' '
+	compound := strings.Builder{}
+	flush := func() {
+		if compound.Len() != 0 {
+			steps = append(steps, selectorStep{combinator, compound.String()})
+			compound.Reset()
+			combinator = ' '
+		}
+	}
+
+	depth, quote := 0, byte(0)
+	for i := 0; i < len(selector); i++ {
+		c := selector[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[' || c == '(':
+			depth++
+		case c == ']' || c == ')':
+			depth--
+		case depth == 0 && (c == '>' || c == '+' || c == '~'):
+			flush()
+			combinator = c
+			continue
+		case depth == 0 && (c == ' ' || c == '\t' || c == '\n'):
+			flush()
+			continue
+		}
+		compound.WriteByte(c)
+	}
+	flush()
+	return steps
+}
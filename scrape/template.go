@@ -0,0 +1,70 @@
+package scrape
+
+import (
+	"errors"
+	"strings"
+)
+
+// TemplateExtractPrefix is a prefix of an extract tag that interpolates the
+// data of the node into the rest of the tag ("tmpl:{@data-brand} {text}
+// ({.size | text})"). A placeholder "{extract}" is replaced by the data
+// extracted from the node by the extract tag, and "{selector | extract}"
+// by the data extracted from the first node matched by the selector
+// inside the node. The data is trimmed. "{{" and "}}" are literal braces.
+const TemplateExtractPrefix = "tmpl:"
+
+// TemplateSeparator separates the selector and the extract tag in
+// a placeholder of the [TemplateExtractPrefix] template.
+const TemplateSeparator = "|"
+
+// extractTemplate returns the template with the placeholders replaced by
+// the data extracted from the first node of the context.
+func (s Scraper) extractTemplate(sc *scraping, ec ExtractContext, template string) (string, error) {
+	ec.Selection = ec.Selection.First()
+	result := strings.Builder{}
+	for rest := template; rest != ""; {
+		i := strings.IndexAny(rest, "{}")
+		if i < 0 {
+			result.WriteString(rest)
+			break
+		}
+		result.WriteString(rest[:i])
+		if strings.HasPrefix(rest[i:], "{{") || strings.HasPrefix(rest[i:], "}}") {
+			result.WriteByte(rest[i])
+			rest = rest[i+2:]
+			continue
+		}
+		end := strings.IndexByte(rest[i:], '}')
+		if rest[i] == '}' || end < 0 {
+			return "", TemplateErr{Template: template, Cause: errors.New("unmatched brace")}
+		}
+
+		val, err := s.extractPlaceholder(sc, ec, rest[i+1:i+end])
+		if err != nil {
+			return "", err
+		}
+		result.WriteString(strings.TrimSpace(val))
+		rest = rest[i+end+1:]
+	}
+	return result.String(), nil
+}
+
+// extractPlaceholder returns the data of a placeholder of a template.
+func (s Scraper) extractPlaceholder(sc *scraping, ec ExtractContext, placeholder string) (string, error) {
+	selector, extract, ok := strings.Cut(placeholder, TemplateSeparator)
+	if !ok {
+		return s.toExtract(sc, ec, strings.TrimSpace(placeholder))
+	}
+
+	f := field{selector: strings.TrimSpace(selector)}
+	ec.Selection = f.find(sc.foreign, ec.Selection)
+	if ec.Selection.Size() == 0 {
+		return "", ScrapingErr{Selector: f.selector, Cause: NoNodesFoundErr{}}
+	}
+	ec.Selection = ec.Selection.First()
+	val, err := s.toExtract(sc, ec, strings.TrimSpace(extract))
+	if err != nil {
+		return "", ScrapingErr{Selector: f.selector, Cause: err}
+	}
+	return val, nil
+}
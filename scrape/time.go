@@ -0,0 +1,122 @@
+package scrape
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+)
+
+// DefaultTimeLayouts are the layouts of times used when a field has no
+// [TimeTag].
+var DefaultTimeLayouts = []string{
+	time.RFC3339Nano,
+	time.RFC3339,
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	time.DateTime,
+	time.DateOnly,
+	time.RFC1123Z,
+	time.RFC1123,
+	time.RFC850,
+	time.RFC822Z,
+	time.RFC822,
+}
+
+// TimeLayoutSeparator separates several layouts in the [TimeTag].
+const TimeLayoutSeparator = "|"
+
+// relativeRegexp matches relative times: "3 hours ago", "an hour ago",
+// "2d ago", "in 5 minutes".
+var relativeRegexp = regexp.MustCompile(`^(in\s+)?(\d+|an?|one)\s*` +
+	`(seconds?|secs?|s|minutes?|mins?|m|hours?|hrs?|h|days?|d|weeks?|wks?|w|months?|mos?|years?|yrs?|y)` +
+	`(\s+ago)?$`)
+
+// ParseTime parses a time that matches one of the layouts (see [time.Parse])
+// or the [DefaultTimeLayouts] if there are no layouts. Relative times are
+// parsed against now: "now", "today", "yesterday", "tomorrow", "3 hours ago",
+// "an hour ago", "2d ago", "in 5 minutes". loc is the location of the times
+// without a time zone and of the relative days; if it is nil, UTC and the
+// location of now are used respectively.
+func ParseTime(s string, layouts []string, loc *time.Location, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if len(layouts) == 0 {
+		layouts = DefaultTimeLayouts
+	}
+	layoutLoc := loc
+	if layoutLoc == nil {
+		layoutLoc = time.UTC
+	}
+	for _, layout := range layouts {
+		if t, err := time.ParseInLocation(layout, s, layoutLoc); err == nil {
+			return t, nil
+		}
+	}
+
+	if loc != nil {
+		now = now.In(loc)
+	}
+	if t, ok := parseRelativeTime(s, now); ok {
+		return t, nil
+	}
+	return time.Time{}, TimeErr{Value: s, Layouts: layouts}
+}
+
+// parseRelativeTime parses a relative time against now.
+func parseRelativeTime(s string, now time.Time) (time.Time, bool) {
+	s = strings.Join(strings.Fields(strings.ToLower(s)), " ")
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch s {
+	case "now", "just now", "right now":
+		return now, true
+	case "today":
+		return today, true
+	case "yesterday":
+		return today.AddDate(0, 0, -1), true
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), true
+	}
+
+	m := relativeRegexp.FindStringSubmatch(s)
+	if m == nil || (m[1] == "") == (m[4] == "") {
+		return time.Time{}, false
+	}
+	n := 1
+	if m[2] != "a" && m[2] != "an" && m[2] != "one" {
+		n, _ = strconv.Atoi(m[2])
+	}
+	if m[4] != "" {
+		n = -n
+	}
+
+	switch unit := strings.TrimSuffix(m[3], "s"); unit {
+	case "second", "sec", "":
+		return now.Add(time.Duration(n) * time.Second), true
+	case "minute", "min", "m":
+		return now.Add(time.Duration(n) * time.Minute), true
+	case "hour", "hr", "h":
+		return now.Add(time.Duration(n) * time.Hour), true
+	case "day", "d":
+		return now.AddDate(0, 0, n), true
+	case "week", "wk", "w":
+		return now.AddDate(0, 0, 7*n), true
+	case "month", "mo":
+		return now.AddDate(0, n, 0), true
+	default:
+		return now.AddDate(n, 0, 0), true
+	}
+}
+
+// timeText returns the machine-readable time of a <time> element (its
+// datetime attribute), or the text of the node.
+func timeText(node *html.Node) string {
+	if node.Type == html.ElementNode && node.Data == "time" {
+		if datetime, err := ExtractAttribute(node, "datetime"); err == nil {
+			return datetime
+		}
+	}
+	return ExtractDeepText(node)
+}
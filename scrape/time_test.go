@@ -0,0 +1,36 @@
+package scrape_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/branow/htmlscraper/scrape"
+	"github.com/branow/tabtest/tab"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTime(t *testing.T) {
+	kyiv, _ := time.LoadLocation("Europe/Kyiv")
+	now := time.Date(2024, 3, 10, 15, 30, 0, 0, time.UTC)
+	args := []tab.Args{
+		{"@default layout", "2024-03-01T10:00:00+02:00", nil, nil, time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC), nil},
+		{"@date only", "2024-03-01", nil, nil, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), nil},
+		{"@second layout", "March 1, 2024", []string{"02.01.2006", "January 2, 2006"}, nil, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), nil},
+		{"@location", "01.03.2024 10:00", []string{"02.01.2006 15:04"}, kyiv, time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC), nil},
+		{"@hours ago", "3 hours ago", nil, nil, now.Add(-3 * time.Hour), nil},
+		{"@an hour ago", "an hour ago", nil, nil, now.Add(-time.Hour), nil},
+		{"@short units", "2d ago", nil, nil, now.AddDate(0, 0, -2), nil},
+		{"@future", "in 2 weeks", nil, nil, now.AddDate(0, 0, 14), nil},
+		{"@months", "5 months ago", nil, nil, now.AddDate(0, -5, 0), nil},
+		{"@yesterday", " Yesterday ", nil, nil, time.Date(2024, 3, 9, 0, 0, 0, 0, time.UTC), nil},
+		{"@yesterday in location", "yesterday", nil, kyiv, time.Date(2024, 3, 9, 0, 0, 0, 0, kyiv), nil},
+		{"@just now", "just now", nil, nil, now, nil},
+		{"@no direction", "3 hours", []string{time.DateOnly}, nil, time.Time{}, TimeErr{Value: "3 hours", Layouts: []string{time.DateOnly}}},
+	}
+	test := func(t *testing.T, s string, layouts []string, loc *time.Location, exp time.Time, eErr error) {
+		act, err := ParseTime(s, layouts, loc, now)
+		assert.Equal(t, eErr, err)
+		assert.True(t, exp.Equal(act), "expected %v, actual %v", exp, act)
+	}
+	tab.RunWithArgs(t, args, test)
+}
@@ -0,0 +1,63 @@
+package scrape
+
+import (
+	"net/url"
+
+	"golang.org/x/net/html"
+)
+
+// baseURL returns the URL of the <base href> element of the document the
+// node belongs to resolved against the given document URL. If there is no
+// <base href> element or its URL is not absolute, the document URL is
+// returned.
+func baseURL(node *html.Node, docURL *url.URL) *url.URL {
+	for node.Parent != nil {
+		node = node.Parent
+	}
+	base := findElement(node, "base", "href")
+	if base == nil {
+		return docURL
+	}
+	href, _ := ExtractAttribute(base, "href")
+	u, err := url.Parse(href)
+	if err != nil {
+		return docURL
+	}
+	if docURL != nil {
+		u = docURL.ResolveReference(u)
+	}
+	if !u.IsAbs() {
+		return docURL
+	}
+	return u
+}
+
+// findElement returns the first element with the given name and attribute
+// among the node and its descendants.
+func findElement(node *html.Node, name, attr string) *html.Node {
+	if node.Type == html.ElementNode && node.Data == name {
+		if _, err := ExtractAttribute(node, attr); err == nil {
+			return node
+		}
+	}
+	for n := node.FirstChild; n != nil; n = n.NextSibling {
+		if found := findElement(n, name, attr); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// resolveURL resolves the reference against the base URL. If the base
+// is nil or the reference is not a valid URL, the reference is returned
+// as is.
+func resolveURL(base *url.URL, ref string) string {
+	if base == nil {
+		return ref
+	}
+	u, err := url.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return base.ResolveReference(u).String()
+}
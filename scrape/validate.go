@@ -0,0 +1,127 @@
+package scrape
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// The tags that let you to reject scraped values that are not valid.
+const (
+	ValidateTag = "validate" // comma-separated rules: "required", "minlen=N", "maxlen=N", "oneof=a|b"
+	PatternTag  = "pattern"  // regular expression that a scraped string must match
+)
+
+// Validator is implemented by types that check themselves after they are
+// scraped (and after [AfterScraper.AfterScrape] is called). The method is
+// called on a pointer to the value.
+type Validator interface {
+	Validate() error
+}
+
+// validate calls the Validate method of the given value if it implements
+// [Validator].
+func validate(ov reflect.Value) error {
+	if !ov.CanAddr() {
+		return nil
+	}
+	if v, ok := ov.Addr().Interface().(Validator); ok {
+		return v.Validate()
+	}
+	return nil
+}
+
+// rule is a validation rule of a field.
+type rule struct {
+	name  string
+	check func(ov reflect.Value) bool
+}
+
+// parseRules returns the rules specified by the validation tags.
+func parseRules(tag reflect.StructTag) ([]rule, error) {
+	rules := []rule{}
+	if pattern, ok := tag.Lookup(PatternTag); ok {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, ValidateTagErr{Rule: PatternTag + "=" + pattern, Cause: err}
+		}
+		rules = append(rules, rule{PatternTag + "=" + pattern, func(ov reflect.Value) bool {
+			return ov.Kind() != reflect.String || re.MatchString(ov.String())
+		}})
+	}
+	for _, r := range strings.Split(tag.Get(ValidateTag), ",") {
+		r = strings.TrimSpace(r)
+		if r == "" {
+			continue
+		}
+		check, err := parseRule(r)
+		if err != nil {
+			return nil, ValidateTagErr{Rule: r, Cause: err}
+		}
+		rules = append(rules, rule{r, check})
+	}
+	return rules, nil
+}
+
+func parseRule(r string) (func(ov reflect.Value) bool, error) {
+	name, arg, _ := strings.Cut(r, "=")
+	switch name {
+	case "required":
+		return func(ov reflect.Value) bool { return !ov.IsZero() }, nil
+	case "minlen", "maxlen":
+		n, err := strconv.Atoi(arg)
+		if err != nil {
+			return nil, err
+		}
+		return func(ov reflect.Value) bool {
+			l, ok := length(ov)
+			return !ok || (name == "minlen" && l >= n) || (name == "maxlen" && l <= n)
+		}, nil
+	case "oneof":
+		values := strings.Split(arg, "|")
+		return func(ov reflect.Value) bool {
+			return ov.Kind() != reflect.String || slices.Contains(values, ov.String())
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown rule")
+	}
+}
+
+// length returns the number of characters of a string or the number of
+// elements of a slice.
+func length(ov reflect.Value) (int, bool) {
+	switch ov.Kind() {
+	case reflect.String:
+		return utf8.RuneCountInString(ov.String()), true
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return ov.Len(), true
+	default:
+		return 0, false
+	}
+}
+
+// validateField checks the scraped value of the field against its rules.
+// Rules are applied to the value a pointer points to, and a nil pointer
+// violates only the "required" rule.
+func validateField(f field, fv reflect.Value) error {
+	if f.rulesErr != nil {
+		return f.rulesErr
+	}
+	for _, r := range f.rules {
+		v := fv
+		for v.Kind() == reflect.Pointer && !v.IsNil() {
+			v = v.Elem()
+		}
+		if v.Kind() == reflect.Pointer && r.name != "required" {
+			continue
+		}
+		if !r.check(v) {
+			return ValidationErr{Field: f.sf.Name, Rule: r.name, Value: v.Interface()}
+		}
+	}
+	return nil
+}
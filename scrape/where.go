@@ -0,0 +1,146 @@
+package scrape
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// WhereTag is a tag of slices and arrays that keeps only the nodes (or the
+// groups, see [GroupTag]) that match the clause. The clauses are:
+//
+//   - has(selector): the node has a descendant matched by the selector; a
+//     group has a node or a descendant of its nodes matched by the selector
+//   - is(selector): the node is matched by the selector
+//   - @name: the node has the attribute
+//   - @name<op>value: the attribute value matches the value; a node without
+//     the attribute matches only the "!=" operator
+//   - text<op>value: the trimmed text of the node matches the value
+//
+// The operators are "=" (equals), "!=" (not equals), "*=" (contains), "^="
+// (starts with), "$=" (ends with), and "=~" (matches the regular expression).
+// A clause starting with "!" is negated: where:"!has(.sold-out)". Names of
+// attributes are matched case-insensitively if [Scraper.IgnoreAttrCase] is set.
+//
+// The nodes are filtered before they are scraped, so the indexes in error
+// messages ("li:n(3)") and of the [IndexExtractTag] are the indexes of the
+// elements of the slice.
+const WhereTag = "where"
+
+// predicate reports whether the node, or the group of nodes, is kept.
+// grouped reports whether the selection is a group (see [GroupTag]).
+type predicate func(sc *scraping, selection *goquery.Selection, grouped bool) bool
+
+// whereOperators are the operators of the [WhereTag] clauses, the ones
+// that are prefixes of others go last.
+var whereOperators = []string{"!=", "*=", "^=", "$=", "=~", "="}
+
+// parseWhere parses the clause of the [WhereTag].
+func parseWhere(clause string) (predicate, error) {
+	clause = strings.TrimSpace(clause)
+	if negated, ok := strings.CutPrefix(clause, "!"); ok {
+		p, err := parseWhere(negated)
+		if err != nil {
+			return nil, err
+		}
+		return func(sc *scraping, selection *goquery.Selection, grouped bool) bool { return !p(sc, selection, grouped) }, nil
+	}
+
+	if selector, ok := cutFunction(clause, "has"); ok {
+		return func(sc *scraping, selection *goquery.Selection, grouped bool) bool {
+			return (grouped && filterSelector(sc.foreign, selection, selector).Size() != 0) ||
+				findSelector(sc.foreign, selection, selector).Size() != 0
+		}, nil
+	}
+	if selector, ok := cutFunction(clause, "is"); ok {
+		return func(sc *scraping, selection *goquery.Selection, _ bool) bool {
+			return filterSelector(sc.foreign, selection.First(), selector).Size() != 0
+		}, nil
+	}
+
+	subject, op, value := clause, "", ""
+	if i := strings.IndexAny(clause, "!*^$=~"); i >= 0 {
+		subject = clause[:i]
+		for _, o := range whereOperators {
+			if rest, ok := strings.CutPrefix(clause[i:], o); ok {
+				op, value = o, unquote(strings.TrimSpace(rest))
+				break
+			}
+		}
+		if op == "" {
+			return nil, WhereTagErr{Clause: clause, Cause: errors.New("unknown operator")}
+		}
+	}
+	subject = strings.TrimSpace(subject)
+
+	var get func(sc *scraping, selection *goquery.Selection) (string, bool)
+	switch {
+	case strings.HasPrefix(subject, AttrExtractTag) && len(subject) > 1:
+		name := subject[1:]
+		get = func(sc *scraping, selection *goquery.Selection) (string, bool) {
+			val, err := sc.attribute(selection.Nodes[0], name)
+			return val, err == nil
+		}
+	case subject == TextExtractTag && op != "":
+		get = func(_ *scraping, selection *goquery.Selection) (string, bool) {
+			return strings.Join(strings.Fields(selection.Text()), " "), true
+		}
+	default:
+		return nil, WhereTagErr{Clause: clause, Cause: errors.New("unknown subject")}
+	}
+
+	match, err := whereMatch(op, value)
+	if err != nil {
+		return nil, WhereTagErr{Clause: clause, Cause: err}
+	}
+	return func(sc *scraping, selection *goquery.Selection, _ bool) bool {
+		val, ok := get(sc, selection)
+		if !ok {
+			return op == "!="
+		}
+		return match(val)
+	}, nil
+}
+
+// whereMatch returns the function that matches values by the operator.
+func whereMatch(op string, value string) (func(val string) bool, error) {
+	switch op {
+	case "":
+		return func(string) bool { return true }, nil
+	case "=":
+		return func(val string) bool { return val == value }, nil
+	case "!=":
+		return func(val string) bool { return val != value }, nil
+	case "*=":
+		return func(val string) bool { return strings.Contains(val, value) }, nil
+	case "^=":
+		return func(val string) bool { return strings.HasPrefix(val, value) }, nil
+	case "$=":
+		return func(val string) bool { return strings.HasSuffix(val, value) }, nil
+	default:
+		regex, err := regexp.Compile(value)
+		if err != nil {
+			return nil, err
+		}
+		return regex.MatchString, nil
+	}
+}
+
+// cutFunction returns the argument of the clause of the form "name(arg)".
+func cutFunction(clause string, name string) (string, bool) {
+	arg, ok := strings.CutPrefix(clause, name+"(")
+	if !ok || !strings.HasSuffix(arg, ")") {
+		return "", false
+	}
+	return strings.TrimSpace(arg[:len(arg)-1]), true
+}
+
+// unquote removes the quotes around the value, if there are any.
+func unquote(value string) string {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+	return value
+}